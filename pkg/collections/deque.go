@@ -0,0 +1,195 @@
+package collections
+
+// Double ended queue on a ring buffer, so pushing and popping at either end
+// doesn't shift or leak the rest of the items. The zero value is ready to use
+type Deque[T any] struct {
+	items []T
+	// Index of the front item, and how many items there are
+	head   int
+	length int
+}
+
+func NewDeque[T any](capacity int) *Deque[T] {
+	return &Deque[T]{items: make([]T, capacity)}
+}
+
+func (d *Deque[T]) Len() int {
+	return d.length
+}
+
+func (d *Deque[T]) IsEmpty() bool {
+	return d.length == 0
+}
+
+// Position in items of the i'th item from the front
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) % len(d.items)
+}
+
+// Doubles the buffer when it's full, unwrapping the items back to the start
+func (d *Deque[T]) grow() {
+	if d.length < len(d.items) {
+		return
+	}
+
+	capacity := len(d.items) * 2
+	if capacity == 0 {
+		capacity = 8
+	}
+
+	items := make([]T, capacity)
+	for i := 0; i < d.length; i += 1 {
+		items[i] = d.items[d.index(i)]
+	}
+
+	d.items = items
+	d.head = 0
+}
+
+func (d *Deque[T]) PushBack(v T) {
+	d.grow()
+	d.items[d.index(d.length)] = v
+	d.length += 1
+}
+
+func (d *Deque[T]) PushFront(v T) {
+	d.grow()
+	d.head = (d.head - 1 + len(d.items)) % len(d.items)
+	d.items[d.head] = v
+	d.length += 1
+}
+
+func (d *Deque[T]) TryPopFront() (T, bool) {
+	var zero T
+	if d.IsEmpty() {
+		return zero, false
+	}
+
+	value := d.items[d.head]
+	// Clear it so the buffer doesn't hold on to it
+	d.items[d.head] = zero
+	d.head = d.index(1)
+	d.length -= 1
+
+	return value, true
+}
+
+func (d *Deque[T]) TryPopBack() (T, bool) {
+	var zero T
+	if d.IsEmpty() {
+		return zero, false
+	}
+
+	last := d.index(d.length - 1)
+	value := d.items[last]
+	d.items[last] = zero
+	d.length -= 1
+
+	return value, true
+}
+
+func (d *Deque[T]) TryPeekFront() (T, bool) {
+	if d.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+
+	return d.items[d.head], true
+}
+
+func (d *Deque[T]) TryPeekBack() (T, bool) {
+	if d.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+
+	return d.items[d.index(d.length-1)], true
+}
+
+// Panics if it's empty
+func (d *Deque[T]) PopFront() T {
+	value, ok := d.TryPopFront()
+	if !ok {
+		panic("deque is empty, cannot pop from the front")
+	}
+
+	return value
+}
+
+// Panics if it's empty
+func (d *Deque[T]) PopBack() T {
+	value, ok := d.TryPopBack()
+	if !ok {
+		panic("deque is empty, cannot pop from the back")
+	}
+
+	return value
+}
+
+// Items from front to back
+func (d *Deque[T]) Items() []T {
+	items := make([]T, d.length)
+	for i := range items {
+		items[i] = d.items[d.index(i)]
+	}
+
+	return items
+}
+
+// First in, first out. The zero value is ready to use
+type Queue[T any] struct {
+	deque Deque[T]
+}
+
+// Add to the back of the queue
+func (q *Queue[T]) Push(v T) {
+	q.deque.PushBack(v)
+}
+
+// Add a group to the back of the queue, in order
+func (q *Queue[T]) PushGroup(values []T) {
+	for _, v := range values {
+		q.deque.PushBack(v)
+	}
+}
+
+// Take from the front of the queue, or false if it's empty
+func (q *Queue[T]) TryPop() (T, bool) {
+	return q.deque.TryPopFront()
+}
+
+// Panics if it's empty
+func (q *Queue[T]) Pop() T {
+	value, ok := q.TryPop()
+	if !ok {
+		panic("queue is empty, cannot pop")
+	}
+
+	return value
+}
+
+// Take up to total items from the front of the queue, in order
+func (q *Queue[T]) PopGroup(total int) []T {
+	values := []T{}
+	for len(values) < total {
+		value, ok := q.TryPop()
+		if !ok {
+			break
+		}
+		values = append(values, value)
+	}
+
+	return values
+}
+
+func (q *Queue[T]) TryPeek() (T, bool) {
+	return q.deque.TryPeekFront()
+}
+
+func (q *Queue[T]) Len() int {
+	return q.deque.Len()
+}
+
+func (q *Queue[T]) IsEmpty() bool {
+	return q.deque.IsEmpty()
+}
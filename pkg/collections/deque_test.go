@@ -0,0 +1,134 @@
+package collections
+
+import (
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// Runs random operations against a Deque and a plain slice, and checks they agree.
+// Small starting capacities make the buffer wrap and grow often
+func TestDequeMatchesSlice(t *testing.T) {
+	check := func(capacity uint8, ops []byte) bool {
+		deque := NewDeque[int](int(capacity % 4))
+		model := []int{}
+
+		for i, op := range ops {
+			switch op % 4 {
+			case 0:
+				deque.PushBack(i)
+				model = append(model, i)
+			case 1:
+				deque.PushFront(i)
+				model = append([]int{i}, model...)
+			case 2:
+				value, ok := deque.TryPopFront()
+				if ok != (len(model) > 0) {
+					return false
+				}
+				if ok {
+					if value != model[0] {
+						return false
+					}
+					model = model[1:]
+				}
+			case 3:
+				value, ok := deque.TryPopBack()
+				if ok != (len(model) > 0) {
+					return false
+				}
+				if ok {
+					if value != model[len(model)-1] {
+						return false
+					}
+					model = model[:len(model)-1]
+				}
+			}
+
+			if deque.Len() != len(model) || deque.IsEmpty() != (len(model) == 0) {
+				return false
+			}
+
+			front, ok := deque.TryPeekFront()
+			if ok && front != model[0] {
+				return false
+			}
+			back, ok := deque.TryPeekBack()
+			if ok && back != model[len(model)-1] {
+				return false
+			}
+		}
+
+		return reflect.DeepEqual(deque.Items(), append([]int{}, model...))
+	}
+
+	if err := quick.Check(check, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+}
+
+// Fills the buffer after moving the head along, so grow has to unwrap it
+func TestDequeGrowWhenWrapped(t *testing.T) {
+	deque := NewDeque[int](4)
+	for i := 0; i < 4; i += 1 {
+		deque.PushBack(i)
+	}
+	deque.PopFront()
+	deque.PopFront()
+	// The head is at 2 now, and these wrap around to the start of the buffer
+	deque.PushBack(4)
+	deque.PushBack(5)
+	if deque.head == 0 {
+		t.Fatal("head should have moved off 0")
+	}
+
+	// Full, so this grows
+	deque.PushBack(6)
+	deque.PushFront(1)
+
+	want := []int{1, 2, 3, 4, 5, 6}
+	if got := deque.Items(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestDequeZeroValue(t *testing.T) {
+	var deque Deque[int]
+	if !deque.IsEmpty() {
+		t.Error("zero value should be empty")
+	}
+	if _, ok := deque.TryPopFront(); ok {
+		t.Error("TryPopFront on an empty deque should fail")
+	}
+	if _, ok := deque.TryPopBack(); ok {
+		t.Error("TryPopBack on an empty deque should fail")
+	}
+
+	deque.PushFront(2)
+	deque.PushFront(1)
+	deque.PushBack(3)
+	if got := deque.Items(); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("got %v, want [1 2 3]", got)
+	}
+}
+
+func TestQueueOrder(t *testing.T) {
+	var queue Queue[int]
+	if _, ok := queue.TryPop(); ok {
+		t.Error("TryPop on an empty queue should fail")
+	}
+
+	queue.PushGroup([]int{1, 2, 3})
+	queue.Push(4)
+	if got := queue.PopGroup(3); !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("got %v, want [1 2 3]", got)
+	}
+
+	// Asking for more than there are just takes what's left
+	if got := queue.PopGroup(5); !reflect.DeepEqual(got, []int{4}) {
+		t.Errorf("got %v, want [4]", got)
+	}
+	if !queue.IsEmpty() {
+		t.Error("queue should be empty")
+	}
+}
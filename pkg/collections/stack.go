@@ -0,0 +1,122 @@
+// Package collections has the containers the puzzles keep needing.
+package collections
+
+import (
+	"fmt"
+)
+
+// Last in, first out. The top of the stack is the end of the slice, so it can be
+// ranged over bottom to top
+type Stack[T any] []T
+
+// Push a single item onto the stack
+func (s *Stack[T]) Push(v T) {
+	*s = append(*s, v)
+}
+
+// Push group onto stack "in order". So the whole
+// group is simply inserted on top, with its last item on top
+func (s *Stack[T]) PushGroup(values []T) {
+	*s = append(*s, values...)
+}
+
+// Pops a single item from the stack, or false if it's empty
+func (s *Stack[T]) TryPop() (T, bool) {
+	if s.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+
+	last := len(*s) - 1
+	value := (*s)[last]
+	*s = (*s)[:last]
+	return value, true
+}
+
+// Pops a single item from the stack. Panics if it's empty
+func (s *Stack[T]) Pop() T {
+	value, ok := s.TryPop()
+	if !ok {
+		panic("stack is empty, cannot pop")
+	}
+
+	return value
+}
+
+// Pops a group of items, keeping their order.
+// So if the total is 3, it will take the top 3 items off
+// and maintain their order. Returns false, leaving the stack alone,
+// if there aren't enough items
+func (s *Stack[T]) TryPopGroup(total int) ([]T, bool) {
+	length := len(*s)
+	if total < 0 || total > length {
+		return nil, false
+	}
+
+	// Copy so pushing onto the stack later can't overwrite what we hand back
+	values := make([]T, total)
+	copy(values, (*s)[length-total:])
+	*s = (*s)[:length-total]
+
+	return values, true
+}
+
+// Same as TryPopGroup, but panics if there aren't enough items
+func (s *Stack[T]) PopGroup(total int) []T {
+	values, ok := s.TryPopGroup(total)
+	if !ok {
+		panic(fmt.Sprintf("cannot pop %v items from a stack of %v", total, len(*s)))
+	}
+
+	return values
+}
+
+// Pops the item at the bottom of the stack, or false if it's empty
+func (s *Stack[T]) TryPopBottom() (T, bool) {
+	if s.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+
+	value := (*s)[0]
+	*s = (*s)[1:]
+	return value, true
+}
+
+// Pops the item at the bottom of the stack. Panics if it's empty
+func (s *Stack[T]) PopBottom() T {
+	value, ok := s.TryPopBottom()
+	if !ok {
+		panic("stack is empty, cannot pop from the bottom")
+	}
+
+	return value
+}
+
+// The item on top, or false if it's empty
+func (s Stack[T]) TryPeek() (T, bool) {
+	if s.IsEmpty() {
+		var zero T
+		return zero, false
+	}
+
+	return s[len(s)-1], true
+}
+
+// The item on top. Panics if it's empty
+func (s Stack[T]) Peek() T {
+	value, ok := s.TryPeek()
+	if !ok {
+		panic("stack is empty, cannot peek")
+	}
+
+	return value
+}
+
+func (s Stack[T]) IsEmpty() bool {
+	return len(s) == 0
+}
+
+func (s Stack[T]) Len() int {
+	return len(s)
+}
@@ -0,0 +1,144 @@
+package collections
+
+import (
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// Runs random operations against a Stack and a plain slice, and checks they agree.
+// Each op byte picks an operation, and values are pushed in order
+func TestStackMatchesSlice(t *testing.T) {
+	check := func(ops []byte, values []int) bool {
+		stack := Stack[int]{}
+		model := []int{}
+		next := 0
+
+		for _, op := range ops {
+			switch op % 5 {
+			case 0:
+				if next >= len(values) {
+					continue
+				}
+				stack.Push(values[next])
+				model = append(model, values[next])
+				next += 1
+			case 1:
+				group := values[next:]
+				if len(group) > int(op%4) {
+					group = group[:op%4]
+				}
+				next += len(group)
+				stack.PushGroup(group)
+				model = append(model, group...)
+			case 2:
+				value, ok := stack.TryPop()
+				if ok != (len(model) > 0) {
+					return false
+				}
+				if ok {
+					if value != model[len(model)-1] {
+						return false
+					}
+					model = model[:len(model)-1]
+				}
+			case 3:
+				total := int(op % 7)
+				group, ok := stack.TryPopGroup(total)
+				if ok != (total <= len(model)) {
+					return false
+				}
+				if ok {
+					// The group keeps its order, so it's the top of the model as is
+					if !reflect.DeepEqual(group, append([]int{}, model[len(model)-total:]...)) {
+						return false
+					}
+					model = model[:len(model)-total]
+				}
+			case 4:
+				value, ok := stack.TryPopBottom()
+				if ok != (len(model) > 0) {
+					return false
+				}
+				if ok {
+					if value != model[0] {
+						return false
+					}
+					model = model[1:]
+				}
+			}
+
+			if stack.Len() != len(model) || stack.IsEmpty() != (len(model) == 0) {
+				return false
+			}
+
+			top, ok := stack.TryPeek()
+			if ok && top != model[len(model)-1] {
+				return false
+			}
+		}
+
+		return len(model) == 0 && stack.IsEmpty() || reflect.DeepEqual([]int(stack), model)
+	}
+
+	if err := quick.Check(check, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+}
+
+// Pushing after popping a group can't change the group that was handed back
+func TestStackPopGroupIsACopy(t *testing.T) {
+	stack := Stack[int]{1, 2, 3}
+	group := stack.PopGroup(2)
+	stack.Push(9)
+	stack.Push(10)
+
+	if !reflect.DeepEqual(group, []int{2, 3}) {
+		t.Errorf("group changed to %v", group)
+	}
+}
+
+func TestStackZeroValue(t *testing.T) {
+	var stack Stack[string]
+	if !stack.IsEmpty() || stack.Len() != 0 {
+		t.Error("zero value should be empty")
+	}
+
+	if _, ok := stack.TryPop(); ok {
+		t.Error("TryPop on an empty stack should fail")
+	}
+	if _, ok := stack.TryPeek(); ok {
+		t.Error("TryPeek on an empty stack should fail")
+	}
+	if _, ok := stack.TryPopGroup(1); ok {
+		t.Error("TryPopGroup(1) on an empty stack should fail")
+	}
+	if group, ok := stack.TryPopGroup(0); !ok || len(group) != 0 {
+		t.Error("TryPopGroup(0) should always work")
+	}
+
+	stack.Push("a")
+	if stack.Pop() != "a" {
+		t.Error("zero value should take pushes")
+	}
+}
+
+func TestStackPanics(t *testing.T) {
+	panics := map[string]func(){
+		"Pop":       func() { (&Stack[int]{}).Pop() },
+		"Peek":      func() { Stack[int]{}.Peek() },
+		"PopGroup":  func() { (&Stack[int]{1}).PopGroup(2) },
+		"PopBottom": func() { (&Stack[int]{}).PopBottom() },
+	}
+
+	for name, f := range panics {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%v should panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
@@ -0,0 +1,85 @@
+package cpu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Anything after one of these on a line is ignored
+const commentMarkers = "#;"
+
+func (i Instruction) String() string {
+	tokens := []string{i.Op}
+	for _, arg := range i.Args {
+		tokens = append(tokens, strconv.Itoa(arg))
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+func stripComment(line string) string {
+	if index := strings.IndexAny(line, commentMarkers); index >= 0 {
+		return line[:index]
+	}
+
+	return line
+}
+
+// Turns source lines into a program, checking every mnemonic and operand count against
+// the instruction set. Blank lines and comments are skipped. Errors have the 1 indexed
+// line number they happened on
+func Assemble(opcodes []Opcode, lines []string) ([]Instruction, error) {
+	opcodeMap := map[string]Opcode{}
+	for _, opcode := range opcodes {
+		opcodeMap[opcode.Name] = opcode
+	}
+
+	program := []Instruction{}
+	for i, line := range lines {
+		tokens := strings.Fields(stripComment(line))
+		if len(tokens) == 0 {
+			continue
+		}
+
+		opcode, exists := opcodeMap[tokens[0]]
+		if !exists {
+			return nil, fmt.Errorf("line %v: unknown mnemonic %q", i+1, tokens[0])
+		}
+
+		args := tokens[1:]
+		if len(args) != opcode.Operands {
+			return nil, fmt.Errorf(
+				"line %v: %v expects %v operands, got %v",
+				i+1,
+				opcode.Name,
+				opcode.Operands,
+				len(args),
+			)
+		}
+
+		instruction := Instruction{Op: opcode.Name}
+		for _, arg := range args {
+			value, err := strconv.Atoi(arg)
+			if err != nil {
+				return nil, fmt.Errorf("line %v: operand %q is not a number", i+1, arg)
+			}
+			instruction.Args = append(instruction.Args, value)
+		}
+
+		program = append(program, instruction)
+	}
+
+	return program, nil
+}
+
+// Turns a program back into source, one instruction per line. Assembling the
+// output gives back the same program
+func Disassemble(program []Instruction) string {
+	lines := make([]string, len(program))
+	for i, instruction := range program {
+		lines[i] = instruction.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
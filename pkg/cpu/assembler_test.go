@@ -0,0 +1,100 @@
+package cpu
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The same shape as the handheld's instruction set
+var assemblerOpcodes = []Opcode{
+	{Name: "noop", Cycles: 1},
+	{Name: "addx", Cycles: 2, Operands: 1},
+}
+
+func TestAssemble(t *testing.T) {
+	tests := []struct {
+		lines []string
+		want  []Instruction
+		err   string
+	}{
+		{
+			lines: []string{"noop", "addx 3", "addx -5"},
+			want: []Instruction{
+				{Op: "noop"},
+				{Op: "addx", Args: []int{3}},
+				{Op: "addx", Args: []int{-5}},
+			},
+		},
+		{
+			lines: []string{"noop # c", "; c", "", "   ", "addx 1 ; c"},
+			want:  []Instruction{{Op: "noop"}, {Op: "addx", Args: []int{1}}},
+		},
+		{
+			lines: []string{"noop", "mul 3"},
+			err:   `line 2: unknown mnemonic "mul"`,
+		},
+		{
+			lines: []string{"# c", "", "addx"},
+			err:   "line 3: addx expects 1 operands, got 0",
+		},
+		{
+			lines: []string{"noop 1"},
+			err:   "line 1: noop expects 0 operands, got 1",
+		},
+		{
+			lines: []string{"noop", "noop", "noop", "addx x"},
+			err:   `line 4: operand "x" is not a number`,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := Assemble(assemblerOpcodes, test.lines)
+		if test.err != "" {
+			if fmt.Sprint(err) != test.err {
+				t.Errorf("%q: got error %v, want %v", test.lines, err, test.err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%q: %v", test.lines, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.lines, got, test.want)
+		}
+	}
+}
+
+func TestDisassembleRoundTrip(t *testing.T) {
+	dat, err := os.ReadFile("../../questions/10/example.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	program, err := Assemble(assemblerOpcodes, strings.Split(string(dat), "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(program) != 146 {
+		t.Fatalf("got %v instructions, want 146", len(program))
+	}
+
+	source := Disassemble(program)
+	again, err := Assemble(assemblerOpcodes, strings.Split(source, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(again, program) {
+		t.Error("assembling the disassembled program gave a different program")
+	}
+
+	// The example is already in the same format
+	if source != strings.TrimRight(string(dat), "\n") {
+		t.Error("disassembling the example didn't give back its source")
+	}
+}
@@ -0,0 +1,190 @@
+// Package cpu is a small cycle-accurate emulator. Opcodes are described by a table,
+// so a new instruction set is just a new table.
+package cpu
+
+import (
+	"fmt"
+)
+
+// Named registers, like "X"
+type Registers map[string]int
+
+// Copy so hooks can keep a snapshot without it changing underneath them
+func (r Registers) Copy() Registers {
+	copied := Registers{}
+	for k, v := range r {
+		copied[k] = v
+	}
+
+	return copied
+}
+
+type Instruction struct {
+	Op   string
+	Args []int
+}
+
+type Opcode struct {
+	Name string
+	// How many cycles the instruction takes. Must be at least 1
+	Cycles int
+	// How many arguments the instruction expects
+	Operands int
+	// Applied at the end of the instruction's last cycle. Can be nil for things like noop
+	Execute func(regs Registers, args []int)
+}
+
+// Called once per cycle with the CPU mid-step
+type Hook func(c *CPU)
+
+type CPU struct {
+	registers Registers
+	initial   Registers
+	opcodes   map[string]Opcode
+
+	program []Instruction
+	// Index of the instruction being run, and how many cycles it's been running for
+	pc      int
+	elapsed int
+	cycle   int
+
+	during []Hook
+	after  []Hook
+}
+
+// Creates a CPU with the given instruction set. Registers start at the values in
+// registers every time a program is loaded
+func New(opcodes []Opcode, registers Registers) *CPU {
+	opcodeMap := map[string]Opcode{}
+	for _, opcode := range opcodes {
+		if opcode.Cycles < 1 {
+			panic(fmt.Sprintf("opcode %v must take at least 1 cycle", opcode.Name))
+		}
+		opcodeMap[opcode.Name] = opcode
+	}
+
+	return &CPU{
+		registers: registers.Copy(),
+		initial:   registers.Copy(),
+		opcodes:   opcodeMap,
+	}
+}
+
+// Hooks run during a cycle, before the current instruction has had any effect
+func (c *CPU) OnDuring(hook Hook) {
+	c.during = append(c.during, hook)
+}
+
+// Hooks run at the end of a cycle, after any instruction that finished has been applied
+func (c *CPU) OnAfter(hook Hook) {
+	c.after = append(c.after, hook)
+}
+
+func (c *CPU) Opcode(name string) (Opcode, bool) {
+	opcode, exists := c.opcodes[name]
+	return opcode, exists
+}
+
+// Checks the program against the instruction set, and resets the CPU to run it
+func (c *CPU) Load(program []Instruction) error {
+	for i, instruction := range program {
+		opcode, exists := c.opcodes[instruction.Op]
+		if !exists {
+			return fmt.Errorf("instruction %v: unknown opcode %q", i, instruction.Op)
+		}
+
+		if len(instruction.Args) != opcode.Operands {
+			return fmt.Errorf(
+				"instruction %v: %v expects %v operands, got %v",
+				i,
+				instruction.Op,
+				opcode.Operands,
+				len(instruction.Args),
+			)
+		}
+	}
+
+	c.program = program
+	c.registers = c.initial.Copy()
+	c.pc = 0
+	c.elapsed = 0
+	c.cycle = 0
+
+	return nil
+}
+
+// The cycle currently running, or the last one that ran. Cycles start at 1
+func (c *CPU) Cycle() int {
+	return c.cycle
+}
+
+func (c *CPU) Register(name string) int {
+	return c.registers[name]
+}
+
+func (c *CPU) Registers() Registers {
+	return c.registers.Copy()
+}
+
+// Index of the instruction being run
+func (c *CPU) PC() int {
+	return c.pc
+}
+
+// The instruction being run, or false if the program is done
+func (c *CPU) Current() (Instruction, bool) {
+	if c.Halted() {
+		return Instruction{}, false
+	}
+
+	return c.program[c.pc], true
+}
+
+func (c *CPU) Halted() bool {
+	return c.pc >= len(c.program)
+}
+
+// Runs a single cycle. Returns false if the program has already finished
+func (c *CPU) Step() bool {
+	if c.Halted() {
+		return false
+	}
+
+	c.cycle += 1
+	for _, hook := range c.during {
+		hook(c)
+	}
+
+	instruction := c.program[c.pc]
+	opcode := c.opcodes[instruction.Op]
+
+	c.elapsed += 1
+	finished := c.elapsed >= opcode.Cycles
+	if finished && opcode.Execute != nil {
+		opcode.Execute(c.registers, instruction.Args)
+	}
+
+	for _, hook := range c.after {
+		hook(c)
+	}
+
+	// Only move on once the after hooks have seen the instruction that finished
+	if finished {
+		c.pc += 1
+		c.elapsed = 0
+	}
+
+	return true
+}
+
+// Loads the program and runs it until it finishes
+func (c *CPU) Run(program []Instruction) error {
+	if err := c.Load(program); err != nil {
+		return err
+	}
+
+	for c.Step() {
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package cpu
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// Nothing like day 10's instruction set, so the loop is checked on its own
+var testOpcodes = []Opcode{
+	{Name: "nop", Cycles: 1},
+	{
+		Name:   "inc",
+		Cycles: 1,
+		Execute: func(regs Registers, args []int) {
+			regs["X"] += 1
+		},
+	},
+	{
+		Name:     "mul",
+		Cycles:   3,
+		Operands: 1,
+		Execute: func(regs Registers, args []int) {
+			regs["Y"] *= args[0]
+		},
+	},
+}
+
+var testProgram = []Instruction{
+	{Op: "inc"},
+	{Op: "mul", Args: []int{4}},
+	{Op: "nop"},
+	{Op: "inc"},
+}
+
+// What a hook saw on one cycle
+type hookCall struct {
+	hook  string
+	cycle int
+	pc    int
+	x     int
+	y     int
+}
+
+func recordHooks(c *CPU) *[]hookCall {
+	calls := &[]hookCall{}
+	record := func(hook string) Hook {
+		return func(c *CPU) {
+			*calls = append(*calls, hookCall{
+				hook:  hook,
+				cycle: c.Cycle(),
+				pc:    c.PC(),
+				x:     c.Register("X"),
+				y:     c.Register("Y"),
+			})
+		}
+	}
+
+	c.OnDuring(record("during"))
+	c.OnAfter(record("after"))
+	return calls
+}
+
+func TestHooksSeeEachCycle(t *testing.T) {
+	c := New(testOpcodes, Registers{"X": 1, "Y": 2})
+	calls := recordHooks(c)
+	if err := c.Run(testProgram); err != nil {
+		t.Fatal(err)
+	}
+
+	// During hooks see the registers before the cycle, after hooks see them once the
+	// instruction that finished has run. mul only changes Y at the end of its third cycle
+	want := []hookCall{
+		{"during", 1, 0, 1, 2}, {"after", 1, 0, 2, 2},
+		{"during", 2, 1, 2, 2}, {"after", 2, 1, 2, 2},
+		{"during", 3, 1, 2, 2}, {"after", 3, 1, 2, 2},
+		{"during", 4, 1, 2, 2}, {"after", 4, 1, 2, 8},
+		{"during", 5, 2, 2, 8}, {"after", 5, 2, 2, 8},
+		{"during", 6, 3, 2, 8}, {"after", 6, 3, 3, 8},
+	}
+
+	if !reflect.DeepEqual(*calls, want) {
+		t.Errorf("got\n%v\nwant\n%v", *calls, want)
+	}
+
+	if !c.Halted() || c.Cycle() != 6 {
+		t.Errorf("got halted %v at cycle %v, want halted at 6", c.Halted(), c.Cycle())
+	}
+	if c.Step() {
+		t.Error("Step after the program finished should return false")
+	}
+}
+
+func TestLoadResetsRegisters(t *testing.T) {
+	c := New(testOpcodes, Registers{"X": 1, "Y": 2})
+	for run := 1; run <= 2; run += 1 {
+		if err := c.Run(testProgram); err != nil {
+			t.Fatal(err)
+		}
+
+		got := c.Registers()
+		if !reflect.DeepEqual(got, Registers{"X": 3, "Y": 8}) {
+			t.Errorf("run %v: got %v, want X 3 and Y 8", run, got)
+		}
+	}
+
+	if err := c.Load(testProgram); err != nil {
+		t.Fatal(err)
+	}
+	if c.Cycle() != 0 || c.PC() != 0 || c.Register("X") != 1 || c.Register("Y") != 2 {
+		t.Errorf("Load didn't reset: cycle %v, pc %v, %v", c.Cycle(), c.PC(), c.Registers())
+	}
+
+	// Registers hands back a copy
+	c.Registers()["X"] = 100
+	if c.Register("X") != 1 {
+		t.Error("changing the copy changed the CPU")
+	}
+}
+
+func TestLoadRejectsBadPrograms(t *testing.T) {
+	tests := []struct {
+		program []Instruction
+		want    string
+	}{
+		{[]Instruction{{Op: "nop"}, {Op: "jmp"}}, `instruction 1: unknown opcode "jmp"`},
+		{[]Instruction{{Op: "mul"}}, "instruction 0: mul expects 1 operands, got 0"},
+		{[]Instruction{{Op: "inc", Args: []int{1}}}, "instruction 0: inc expects 0 operands, got 1"},
+	}
+
+	for _, test := range tests {
+		c := New(testOpcodes, Registers{})
+		err := c.Load(test.program)
+		if fmt.Sprint(err) != test.want {
+			t.Errorf("got %v, want %v", err, test.want)
+		}
+	}
+}
+
+func TestNewRejectsZeroCycleOpcodes(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("an opcode with 0 cycles should panic")
+		}
+	}()
+
+	New([]Opcode{{Name: "free", Cycles: 0}}, Registers{})
+}
@@ -0,0 +1,121 @@
+// Package interval works with inclusive ranges of whole numbers, like the section
+// assignments in the puzzles.
+package interval
+
+import (
+	"fmt"
+)
+
+// Start to End, both included. An interval with End before Start is empty
+type Interval struct {
+	Start int
+	End   int
+}
+
+// Creates an interval from start to end. It's empty if end is before start
+func New(start int, end int) Interval {
+	return Interval{Start: start, End: end}
+}
+
+func (i Interval) IsEmpty() bool {
+	return i.End < i.Start
+}
+
+// How many whole numbers are in the interval
+func (i Interval) Len() int {
+	if i.IsEmpty() {
+		return 0
+	}
+
+	return i.End - i.Start + 1
+}
+
+func (i Interval) ContainsPoint(x int) bool {
+	return i.Start <= x && x <= i.End
+}
+
+// If every number in other is also in i. The empty interval is in everything
+func (i Interval) Contains(other Interval) bool {
+	if other.IsEmpty() {
+		return true
+	}
+
+	return i.Start <= other.Start && other.End <= i.End
+}
+
+// If the two share at least one number
+func (i Interval) Overlaps(other Interval) bool {
+	_, ok := i.Intersect(other)
+	return ok
+}
+
+// The numbers in both, or false if there aren't any
+func (i Interval) Intersect(other Interval) (Interval, bool) {
+	intersection := Interval{Start: max(i.Start, other.Start), End: min(i.End, other.End)}
+	if intersection.IsEmpty() {
+		return Interval{}, false
+	}
+
+	return intersection, true
+}
+
+// The numbers in either, as a single interval. Returns false if there'd be a gap
+// between them. Intervals that touch, like 1-3 and 4-6, join up since there's no
+// whole number between them
+func (i Interval) Union(other Interval) (Interval, bool) {
+	if i.IsEmpty() {
+		return other, true
+	}
+
+	if other.IsEmpty() {
+		return i, true
+	}
+
+	if i.End+1 < other.Start || other.End+1 < i.Start {
+		return Interval{}, false
+	}
+
+	return Interval{Start: min(i.Start, other.Start), End: max(i.End, other.End)}, true
+}
+
+// The numbers in i that aren't in other. Taking out the middle leaves two pieces
+func (i Interval) Subtract(other Interval) []Interval {
+	if i.IsEmpty() {
+		return []Interval{}
+	}
+
+	if !i.Overlaps(other) {
+		return []Interval{i}
+	}
+
+	pieces := []Interval{}
+	if i.Start < other.Start {
+		pieces = append(pieces, Interval{Start: i.Start, End: other.Start - 1})
+	}
+
+	if other.End < i.End {
+		pieces = append(pieces, Interval{Start: other.End + 1, End: i.End})
+	}
+
+	return pieces
+}
+
+func (i Interval) String() string {
+	return fmt.Sprintf("%v-%v", i.Start, i.End)
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
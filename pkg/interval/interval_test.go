@@ -0,0 +1,88 @@
+package interval
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIntersect(t *testing.T) {
+	tests := []struct {
+		a, b Interval
+		want Interval
+		ok   bool
+	}{
+		{New(2, 8), New(3, 7), New(3, 7), true},
+		{New(5, 7), New(7, 9), New(7, 7), true},
+		{New(2, 4), New(6, 8), Interval{}, false},
+		{New(1, 3), New(4, 6), Interval{}, false},
+		{New(1, 5), New(3, 2), Interval{}, false},
+	}
+
+	for _, test := range tests {
+		got, ok := test.a.Intersect(test.b)
+		if got != test.want || ok != test.ok {
+			t.Errorf("%v ∩ %v: got %v, %v, want %v, %v", test.a, test.b, got, ok, test.want, test.ok)
+		}
+
+		if test.a.Overlaps(test.b) != test.ok {
+			t.Errorf("%v overlaps %v: got %v, want %v", test.a, test.b, !test.ok, test.ok)
+		}
+	}
+}
+
+func TestUnion(t *testing.T) {
+	tests := []struct {
+		a, b Interval
+		want Interval
+		ok   bool
+	}{
+		{New(1, 3), New(4, 6), New(1, 6), true},
+		{New(4, 6), New(1, 3), New(1, 6), true},
+		{New(1, 5), New(3, 9), New(1, 9), true},
+		{New(1, 3), New(5, 6), Interval{}, false},
+		{New(3, 2), New(5, 6), New(5, 6), true},
+		{New(5, 6), New(3, 2), New(5, 6), true},
+	}
+
+	for _, test := range tests {
+		got, ok := test.a.Union(test.b)
+		if got != test.want || ok != test.ok {
+			t.Errorf("%v ∪ %v: got %v, %v, want %v, %v", test.a, test.b, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestSubtract(t *testing.T) {
+	tests := []struct {
+		a, b Interval
+		want []Interval
+	}{
+		{New(1, 10), New(4, 6), []Interval{New(1, 3), New(7, 10)}},
+		{New(1, 10), New(1, 6), []Interval{New(7, 10)}},
+		{New(1, 10), New(6, 12), []Interval{New(1, 5)}},
+		{New(3, 5), New(1, 10), []Interval{}},
+		{New(1, 3), New(5, 6), []Interval{New(1, 3)}},
+		{New(3, 2), New(1, 10), []Interval{}},
+	}
+
+	for _, test := range tests {
+		if got := test.a.Subtract(test.b); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%v - %v: got %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	empty := New(5, 4)
+	if !empty.IsEmpty() || empty.Len() != 0 {
+		t.Errorf("%v: got empty %v, length %v", empty, empty.IsEmpty(), empty.Len())
+	}
+
+	if !New(1, 3).Contains(empty) {
+		t.Errorf("1-3 doesn't contain the empty interval")
+	}
+
+	if single := New(4, 4); single.IsEmpty() || single.Len() != 1 {
+		t.Errorf("%v: got empty %v, length %v", single, single.IsEmpty(), single.Len())
+	}
+}
@@ -0,0 +1,162 @@
+package interval
+
+import (
+	"sort"
+	"strings"
+)
+
+// A set of whole numbers, kept as sorted intervals that don't overlap or touch.
+// The zero value is an empty set
+type Set struct {
+	intervals []Interval
+}
+
+func NewSet(intervals ...Interval) *Set {
+	set := &Set{}
+	for _, interval := range intervals {
+		set.Add(interval)
+	}
+
+	return set
+}
+
+// Adds the interval, merging it with any it overlaps or touches
+func (s *Set) Add(interval Interval) {
+	if interval.IsEmpty() {
+		return
+	}
+
+	merged := []Interval{}
+	inserted := false
+	for _, existing := range s.intervals {
+		if union, ok := existing.Union(interval); ok {
+			interval = union
+			continue
+		}
+
+		// Keep them sorted, so slot it in before the first interval past it
+		if !inserted && interval.End < existing.Start {
+			merged = append(merged, interval)
+			inserted = true
+		}
+		merged = append(merged, existing)
+	}
+
+	if !inserted {
+		merged = append(merged, interval)
+	}
+
+	s.intervals = merged
+}
+
+// Takes the interval's numbers out of the set
+func (s *Set) Remove(interval Interval) {
+	remaining := []Interval{}
+	for _, existing := range s.intervals {
+		remaining = append(remaining, existing.Subtract(interval)...)
+	}
+
+	s.intervals = remaining
+}
+
+func (s *Set) ContainsPoint(x int) bool {
+	// First interval that ends at or after x
+	index := sort.Search(len(s.intervals), func(i int) bool {
+		return s.intervals[i].End >= x
+	})
+
+	return index < len(s.intervals) && s.intervals[index].ContainsPoint(x)
+}
+
+// If every number in the interval is in the set
+func (s *Set) Contains(interval Interval) bool {
+	if interval.IsEmpty() {
+		return true
+	}
+
+	for _, existing := range s.intervals {
+		if existing.Contains(interval) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Set) IsEmpty() bool {
+	return len(s.intervals) == 0
+}
+
+// How many numbers are in the set
+func (s *Set) Len() int {
+	total := 0
+	for _, interval := range s.intervals {
+		total += interval.Len()
+	}
+
+	return total
+}
+
+// A copy of the intervals, in order
+func (s *Set) Intervals() []Interval {
+	return append([]Interval{}, s.intervals...)
+}
+
+// From the smallest number in the set to the largest, or false if it's empty
+func (s *Set) Span() (Interval, bool) {
+	if s.IsEmpty() {
+		return Interval{}, false
+	}
+
+	return Interval{Start: s.intervals[0].Start, End: s.intervals[len(s.intervals)-1].End}, true
+}
+
+// Numbers in either set
+func (s *Set) Union(other *Set) *Set {
+	union := NewSet(s.intervals...)
+	for _, interval := range other.intervals {
+		union.Add(interval)
+	}
+
+	return union
+}
+
+// Numbers in both sets
+func (s *Set) Intersect(other *Set) *Set {
+	intersection := &Set{}
+
+	// Both are sorted, so walk them together
+	for i, j := 0, 0; i < len(s.intervals) && j < len(other.intervals); {
+		a, b := s.intervals[i], other.intervals[j]
+		if overlap, ok := a.Intersect(b); ok {
+			intersection.intervals = append(intersection.intervals, overlap)
+		}
+
+		if a.End < b.End {
+			i += 1
+		} else {
+			j += 1
+		}
+	}
+
+	return intersection
+}
+
+// Numbers in s that aren't in other
+func (s *Set) Subtract(other *Set) *Set {
+	difference := NewSet(s.intervals...)
+	for _, interval := range other.intervals {
+		difference.Remove(interval)
+	}
+
+	return difference
+}
+
+func (s *Set) String() string {
+	parts := []string{}
+	for _, interval := range s.intervals {
+		parts = append(parts, interval.String())
+	}
+
+	return "{" + strings.Join(parts, ", ") + "}"
+}
@@ -0,0 +1,66 @@
+package interval
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetAdd(t *testing.T) {
+	tests := []struct {
+		add  []Interval
+		want []Interval
+	}{
+		{[]Interval{New(10, 12), New(1, 2), New(5, 6)}, []Interval{New(1, 2), New(5, 6), New(10, 12)}},
+		{[]Interval{New(1, 2), New(10, 12), New(3, 9)}, []Interval{New(1, 12)}},
+		{[]Interval{New(1, 2), New(5, 6), New(4, 4)}, []Interval{New(1, 2), New(4, 6)}},
+		{[]Interval{New(1, 3), New(4, 6)}, []Interval{New(1, 6)}},
+		{[]Interval{New(3, 2), New(1, 1)}, []Interval{New(1, 1)}},
+		{[]Interval{}, []Interval{}},
+	}
+
+	for _, test := range tests {
+		if got := NewSet(test.add...).Intervals(); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("adding %v: got %v, want %v", test.add, got, test.want)
+		}
+	}
+}
+
+func TestSetRemove(t *testing.T) {
+	set := NewSet(New(1, 10), New(20, 30))
+	set.Remove(New(5, 25))
+
+	want := []Interval{New(1, 4), New(26, 30)}
+	if got := set.Intervals(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if set.Len() != 9 || set.ContainsPoint(5) || !set.ContainsPoint(26) {
+		t.Errorf("%v: got length %v, has 5 %v, has 26 %v",
+			set, set.Len(), set.ContainsPoint(5), set.ContainsPoint(26))
+	}
+}
+
+func TestSetIntersect(t *testing.T) {
+	a := NewSet(New(1, 5), New(8, 12), New(20, 22))
+	b := NewSet(New(4, 9), New(11, 21))
+
+	want := []Interval{New(4, 5), New(8, 9), New(11, 12), New(20, 21)}
+	if got := a.Intersect(b).Intervals(); !reflect.DeepEqual(got, want) {
+		t.Errorf("%v ∩ %v: got %v, want %v", a, b, got, want)
+	}
+
+	if got := a.Intersect(&Set{}); !got.IsEmpty() {
+		t.Errorf("%v ∩ {}: got %v", a, got)
+	}
+}
+
+func TestSetSpan(t *testing.T) {
+	set := NewSet(New(8, 12), New(1, 3))
+	if span, ok := set.Span(); span != New(1, 12) || !ok {
+		t.Errorf("%v: got span %v, %v", set, span, ok)
+	}
+
+	if span, ok := (&Set{}).Span(); ok {
+		t.Errorf("empty set: got span %v", span)
+	}
+}
@@ -0,0 +1,118 @@
+// Package logger is a small leveled logger for debug output. It's quiet unless asked,
+// and writes to stderr so it doesn't get mixed up with the answers.
+package logger
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type Level int
+
+const (
+	// Only the answers
+	Quiet Level = iota
+	// What got parsed out of the input, with -v
+	Verbose
+	// Every step the solver takes, with -vv
+	Trace
+)
+
+type Logger struct {
+	level Level
+	out   io.Writer
+}
+
+func New(level Level, out io.Writer) *Logger {
+	return &Logger{level: level, out: out}
+}
+
+func (l *Logger) SetLevel(level Level) {
+	l.level = level
+}
+
+// If messages at this level will be written. Handy for skipping expensive output
+func (l *Logger) Enabled(level Level) bool {
+	return level != Quiet && level <= l.level
+}
+
+func (l *Logger) logf(level Level, format string, args ...any) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	message := fmt.Sprintf(format, args...)
+	if !strings.HasSuffix(message, "\n") {
+		message += "\n"
+	}
+
+	io.WriteString(l.out, message)
+}
+
+func (l *Logger) Verbosef(format string, args ...any) {
+	l.logf(Verbose, format, args...)
+}
+
+func (l *Logger) Tracef(format string, args ...any) {
+	l.logf(Trace, format, args...)
+}
+
+// The logger the package functions and flags use
+var std = New(Quiet, os.Stderr)
+
+func Default() *Logger {
+	return std
+}
+
+func SetLevel(level Level) {
+	std.SetLevel(level)
+}
+
+func Enabled(level Level) bool {
+	return std.Enabled(level)
+}
+
+func Verbosef(format string, args ...any) {
+	std.Verbosef(format, args...)
+}
+
+func Tracef(format string, args ...any) {
+	std.Tracef(format, args...)
+}
+
+// A bool flag that raises the default logger to its level when it's set
+type levelFlag struct {
+	level Level
+	set   bool
+}
+
+func (f *levelFlag) String() string {
+	return fmt.Sprint(f.set)
+}
+
+func (f *levelFlag) IsBoolFlag() bool {
+	return true
+}
+
+func (f *levelFlag) Set(s string) error {
+	if s != "true" && s != "false" {
+		return fmt.Errorf("%q should be true or false", s)
+	}
+
+	f.set = s == "true"
+	// Never lower it, so -v -vv is the same as -vv
+	if f.set && std.level < f.level {
+		std.SetLevel(f.level)
+	}
+
+	return nil
+}
+
+// Adds -v and -vv to the command line flags. Call before flag.Parse
+func RegisterFlags() {
+	flag.Var(&levelFlag{level: Verbose}, "v", "log what gets parsed from the input")
+	flag.Var(&levelFlag{level: Trace}, "vv", "log every step the solver takes")
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"questions/01/main/pkg/logger"
+)
+
+// Rucksacks that carry the same badge. Members are bundle numbers. There should be
+// one badge, but groups that share more have all of them, lowest priority first
+type Group struct {
+	members []int
+	badges  []rune
+}
+
+func (g Group) toString() string {
+	members := []string{}
+	for _, member := range g.members {
+		members = append(members, fmt.Sprint(member+1))
+	}
+
+	label := "badge"
+	if len(g.badges) > 1 {
+		label = "badges"
+	}
+
+	return fmt.Sprintf(
+		"rucksacks %v: %v %v, priority %v",
+		strings.Join(members, ", "),
+		label,
+		string(g.badges),
+		priorityOf(g.badges),
+	)
+}
+
+// Splits the rucksacks into groups in the order they were listed. With strict, each
+// group has to share exactly one item
+func groupInOrder(bundles []Bundle, size int, strict bool) ([]Group, error) {
+	if size < 1 || len(bundles)%size != 0 {
+		return nil, fmt.Errorf("%v rucksacks can't be split into groups of %v", len(bundles), size)
+	}
+
+	groups := []Group{}
+	for i := 0; i < len(bundles); i += size {
+		badges := findCommonInBundles(bundles[i : i+size])
+		if err := checkShared(badges, strict); err != nil {
+			return nil, fmt.Errorf("group %v: %w", i/size+1, err)
+		}
+
+		members := []int{}
+		for _, bundle := range bundles[i : i+size] {
+			members = append(members, bundle.bundleNum)
+		}
+		groups = append(groups, Group{members: members, badges: badges})
+	}
+
+	return groups, nil
+}
+
+// Every way of picking size rucksacks that share exactly one item. members holds each
+// candidate's rucksacks back to back, and byBundle lists the candidates each rucksack is in
+type candidates struct {
+	size     int
+	members  []int
+	badges   []rune
+	byBundle [][]int
+}
+
+func (c *candidates) group(index int) []int {
+	return c.members[index*c.size : (index+1)*c.size]
+}
+
+// Bigger groups have far more combinations to try, so give up rather than run out of memory
+const maxCandidates = 2000000
+
+func findCandidates(bundles []Bundle, size int) (*candidates, error) {
+	c := &candidates{size: size, byBundle: make([][]int, len(bundles))}
+	picked := []int{}
+
+	var pick func(from int, common ItemSet) bool
+	pick = func(from int, common ItemSet) bool {
+		if len(picked) == size {
+			if common.size() != 1 {
+				return true
+			}
+
+			index := len(c.badges)
+			c.members = append(c.members, picked...)
+			c.badges = append(c.badges, common.first())
+			for _, member := range picked {
+				c.byBundle[member] = append(c.byBundle[member], index)
+			}
+			return len(c.badges) < maxCandidates
+		}
+
+		for i := from; i < len(bundles); i += 1 {
+			// Adding more rucksacks can only shrink what's shared
+			next := common & bundles[i].all
+			if next.isEmpty() {
+				continue
+			}
+
+			picked = append(picked, i)
+			ok := pick(i+1, next)
+			picked = picked[:len(picked)-1]
+			if !ok {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if !pick(0, ^ItemSet(0)) {
+		return nil, fmt.Errorf(
+			"more than %v candidate groups of %v, too many to search",
+			maxCandidates,
+			size,
+		)
+	}
+
+	return c, nil
+}
+
+// For when the rucksacks aren't listed in their groups. Finds a way to split every
+// rucksack into groups that share exactly one item. It's an exact cover, so the search
+// always places the rucksack with the fewest groups left to pick from. There's usually
+// more than one answer, so the input order wins if it works, otherwise it's the first found
+func findGrouping(bundles []Bundle, size int) ([]Group, error) {
+	if size < 1 || len(bundles)%size != 0 {
+		return nil, fmt.Errorf("%v rucksacks can't be split into groups of %v", len(bundles), size)
+	}
+
+	if groups, err := groupInOrder(bundles, size, true); err == nil {
+		return groups, nil
+	}
+
+	c, err := findCandidates(bundles, size)
+	if err != nil {
+		return nil, err
+	}
+	logger.Verbosef("%v candidate groups of %v", len(c.badges), size)
+
+	placed := make([]bool, len(bundles))
+	// How many placed rucksacks each candidate has. Only candidates with none can be picked
+	blocked := make([]int, len(c.badges))
+	// How many pickable candidates each rucksack is in
+	live := make([]int, len(bundles))
+	for i, list := range c.byBundle {
+		live[i] = len(list)
+	}
+
+	// Walks every candidate sharing a rucksack with the chosen one, blocking or unblocking it
+	update := func(chosen int, delta int) {
+		for _, member := range c.group(chosen) {
+			placed[member] = delta > 0
+			for _, other := range c.byBundle[member] {
+				before := blocked[other]
+				blocked[other] += delta
+				if before == 0 || blocked[other] == 0 {
+					for _, x := range c.group(other) {
+						live[x] -= delta
+					}
+				}
+			}
+		}
+	}
+
+	chosen := []int{}
+	var search func() bool
+	search = func() bool {
+		next := -1
+		for i := range bundles {
+			if !placed[i] && (next < 0 || live[i] < live[next]) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return true
+		}
+
+		for _, candidate := range c.byBundle[next] {
+			if blocked[candidate] != 0 {
+				continue
+			}
+
+			chosen = append(chosen, candidate)
+			update(candidate, 1)
+			if search() {
+				return true
+			}
+			update(candidate, -1)
+			chosen = chosen[:len(chosen)-1]
+		}
+
+		return false
+	}
+
+	if !search() {
+		return nil, fmt.Errorf(
+			"no way to split %v rucksacks into groups of %v that share exactly one item",
+			len(bundles),
+			size,
+		)
+	}
+
+	groups := []Group{}
+	for _, candidate := range chosen {
+		members := append([]int{}, c.group(candidate)...)
+		groups = append(groups, Group{members: members, badges: []rune{c.badges[candidate]}})
+	}
+
+	return groups, nil
+}
+
+func printBadgeReport(w io.Writer, groups []Group) {
+	total := 0
+	for i, group := range groups {
+		fmt.Fprintf(w, "group %v: %v\n", i+1, group.toString())
+		total += priorityOf(group.badges)
+	}
+	fmt.Fprintf(w, "%v groups, priority total %v\n", len(groups), total)
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Every item, in priority order
+const allItems = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// Writes rucksacks shaped like the puzzle's, in groups of 3. Each rucksack's compartments
+// share exactly one item, and each group shares exactly one badge
+func generateRucksacks(total int, seed int64, w io.Writer) error {
+	random := rand.New(rand.NewSource(seed))
+	pick := func(pool []rune) rune {
+		return pool[random.Intn(len(pool))]
+	}
+
+	for i := 0; i < total; i += 3 {
+		items := []rune(allItems)
+		random.Shuffle(len(items), func(a, b int) {
+			items[a], items[b] = items[b], items[a]
+		})
+
+		// Give each rucksack in the group its own items so only the badge is shared
+		badge := items[0]
+		poolSize := (len(items) - 1) / 3
+		for elf := 0; elf < 3 && i+elf < total; elf += 1 {
+			pool := items[1+elf*poolSize : 1+(elf+1)*poolSize]
+			common := pool[0]
+			startPool, endPool := pool[1:poolSize/2], pool[poolSize/2:]
+
+			half := 8 + random.Intn(16)
+			start := []rune{common, badge}
+			end := []rune{common}
+			for len(start) < half {
+				start = append(start, pick(startPool))
+			}
+			for len(end) < half {
+				end = append(end, pick(endPool))
+			}
+
+			random.Shuffle(len(start), func(a, b int) { start[a], start[b] = start[b], start[a] })
+			random.Shuffle(len(end), func(a, b int) { end[a], end[b] = end[b], end[a] })
+
+			if i+elf > 0 {
+				if _, err := io.WriteString(w, "\n"); err != nil {
+					return err
+				}
+			}
+
+			if _, err := io.WriteString(w, string(start)+string(end)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// How the rucksacks used to be checked, with a map of counts per compartment
+func mapScores(rucksacks []string) (int, int) {
+	countItems := func(s string) map[rune]int {
+		count := map[rune]int{}
+		for _, c := range s {
+			count[c] += 1
+		}
+		return count
+	}
+
+	score := 0
+	for _, rucksack := range rucksacks {
+		half := len(rucksack) / 2
+		start, end := countItems(rucksack[:half]), countItems(rucksack[half:])
+		for item := range start {
+			if end[item] > 0 {
+				score += int(convertItemToValue(item))
+				break
+			}
+		}
+	}
+
+	badges := 0
+	for i := 0; i+3 <= len(rucksacks); i += 3 {
+		seen := map[rune]int{}
+		for _, rucksack := range rucksacks[i : i+3] {
+			for item := range countItems(rucksack) {
+				seen[item] += 1
+			}
+		}
+
+		for item, count := range seen {
+			if count == 3 {
+				badges += int(convertItemToValue(item))
+				break
+			}
+		}
+	}
+
+	return score, badges
+}
+
+// Same as parts, but with the item sets
+func bitsetScores(rucksacks []string) (int, int) {
+	bundles, err := createBundles(rucksacks)
+	if err != nil {
+		panic(err)
+	}
+
+	// Straight to the sets, so the benchmark isn't timing slice allocations
+	// Empty sets score nothing, the same as the other scorers
+	score := 0
+	for _, bundle := range bundles {
+		if shared := bundle.start & bundle.end; !shared.isEmpty() {
+			score += int(convertItemToValue(shared.first()))
+		}
+	}
+
+	badges := 0
+	for i := 0; i+3 <= len(bundles); i += 3 {
+		common := bundles[i].all & bundles[i+1].all & bundles[i+2].all
+		if !common.isEmpty() {
+			badges += int(convertItemToValue(common.first()))
+		}
+	}
+
+	return score, badges
+}
+
+type scorer struct {
+	name   string
+	scores func([]string) (int, int)
+}
+
+var scorers = []scorer{
+	{"maps", mapScores},
+	{"bitsets", bitsetScores},
+}
+
+// Scores the rucksacks b.N times. Shared by the -bench mode and go test -bench
+func benchmarkScorer(s scorer, rucksacks []string, size int) func(b *testing.B) {
+	return func(b *testing.B) {
+		b.SetBytes(int64(size))
+		for i := 0; i < b.N; i += 1 {
+			s.scores(rucksacks)
+		}
+	}
+}
+
+// Generated rucksacks, along with how many bytes they take up
+func generatedRucksacks(total int, seed int64) ([]string, int, error) {
+	var sb strings.Builder
+	if err := generateRucksacks(total, seed, &sb); err != nil {
+		return nil, 0, err
+	}
+
+	return strings.Split(sb.String(), "\n"), sb.Len(), nil
+}
+
+// Benchmarks the map and bitset approaches on the same generated rucksacks
+func runBenchmark(total int, seed int64, w io.Writer) error {
+	rucksacks, size, err := generatedRucksacks(total, seed)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%v rucksacks, %v bytes\n", len(rucksacks), size)
+
+	for _, s := range scorers {
+		result := testing.Benchmark(benchmarkScorer(s, rucksacks, size))
+		elapsed := time.Duration(result.NsPerOp())
+		score, badges := s.scores(rucksacks)
+
+		fmt.Fprintf(
+			w,
+			"  %-8v %12v  %8.1f MB/s  %4v runs  part 1 %v, part 2 %v\n",
+			s.name,
+			elapsed.Round(time.Microsecond),
+			float64(size)/elapsed.Seconds()/1e6,
+			result.N,
+			score,
+			badges,
+		)
+	}
+
+	return nil
+}
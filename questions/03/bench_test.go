@@ -0,0 +1,17 @@
+package main
+
+import (
+	"testing"
+)
+
+// Same rucksacks as the -bench mode, run with go test -bench .
+func BenchmarkScorers(b *testing.B) {
+	rucksacks, size, err := generatedRucksacks(100000, 1)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, s := range scorers {
+		b.Run(s.name, benchmarkScorer(s, rucksacks, size))
+	}
+}
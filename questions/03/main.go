@@ -1,16 +1,56 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"math/bits"
 	"os"
 	"strings"
+
+	"questions/01/main/pkg/logger"
 )
 
+// One bit per item, at the item's priority. Bit 0 is never used
+type ItemSet uint64
+
+func (s ItemSet) add(item rune) ItemSet {
+	return s | 1<<uint(convertItemToValue(item))
+}
+
+func (s ItemSet) isEmpty() bool {
+	return s == 0
+}
+
+// The items in the set, lowest priority first
+func (s ItemSet) items() []rune {
+	items := []rune{}
+	for value := 1; value <= 52; value += 1 {
+		if s&(1<<uint(value)) != 0 {
+			items = append(items, convertValueToItem(rune(value)))
+		}
+	}
+
+	return items
+}
+
+func (s ItemSet) size() int {
+	return bits.OnesCount64(uint64(s))
+}
+
+// The lowest priority item in the set. Panics if it's empty, since there's no item to give
+func (s ItemSet) first() rune {
+	if s.isEmpty() {
+		panic("empty item set has no first item")
+	}
+
+	return convertValueToItem(rune(bits.TrailingZeros64(uint64(s))))
+}
+
 type Bundle struct {
 	full      string
-	start     map[rune]int
-	end       map[rune]int
-	all       map[rune]int
+	start     ItemSet
+	end       ItemSet
+	all       ItemSet
 	bundleNum int
 }
 
@@ -23,120 +63,211 @@ func readLines(inputFile string) []string {
 	return strings.Split(string(dat), "\n")
 }
 
-func countItems(s string) map[rune]int {
-	count := map[rune]int{}
-
+func itemsOf(s string) ItemSet {
+	set := ItemSet(0)
 	for _, c := range s {
-		count[c] += 1
+		set = set.add(c)
 	}
 
-	return count
+	return set
 }
 
-// Finds the first common char in the bundle
-func findFirstCommon(bundle Bundle) rune {
-	for k, _ := range bundle.start {
-		if bundle.end[k] > 0 {
-			return k
-		}
+// Every item both compartments have, lowest priority first
+func findCommon(bundle Bundle) []rune {
+	return (bundle.start & bundle.end).items()
+}
+
+// Every item all the bundles have, lowest priority first
+func findCommonInBundles(bundles []Bundle) []rune {
+	common := ^ItemSet(0)
+	for _, bundle := range bundles {
+		common &= bundle.all
 	}
 
-	panic(
-		fmt.Sprint(
-			"No common char found in bundle ",
-			bundle.bundleNum,
-			len(bundle.start),
-			len(bundle.end),
-		),
-	)
+	return common.items()
 }
 
-func findCommonInBundles(bundles []Bundle) rune {
-	set := map[rune]int{}
+// Something always has to be shared. The puzzle promises exactly one item, which strict
+// holds the input to
+func checkShared(items []rune, strict bool) error {
+	if len(items) == 0 {
+		return fmt.Errorf("no shared items")
+	}
 
-	for _, bundle := range bundles {
-		for k, v := range bundle.all {
-			if v >= 0 {
-				set[k] += 1
-			}
-		}
+	if strict && len(items) != 1 {
+		return fmt.Errorf("expected 1 shared item, found %v: %q", len(items), string(items))
 	}
 
-	for k, v := range set {
-		if v == len(bundles) {
-			return k
-		}
+	return nil
+}
+
+// The priorities of the items added up
+func priorityOf(items []rune) int {
+	total := 0
+	for _, item := range items {
+		total += int(convertItemToValue(item))
 	}
 
-	panic(
-		fmt.Sprint(
-			"No common char found in bundles",
-			bundles[0].bundleNum,
-		),
-	)
+	return total
 }
 
-// Assume input is even
-func createBundle(input string, bundleNum int) Bundle {
+// Both compartments are the same size, so rucksacks must have an even number of items
+func createBundle(input string, bundleNum int) (Bundle, error) {
 	length := len(input)
-	halfway := (length / 2) - 1
+	if length == 0 {
+		return Bundle{}, fmt.Errorf("rucksack %v: empty", bundleNum+1)
+	}
+
+	if length%2 != 0 {
+		return Bundle{}, fmt.Errorf(
+			"rucksack %v: %v items can't be split into two compartments",
+			bundleNum+1,
+			length,
+		)
+	}
+
+	for i, item := range input {
+		if !isItem(item) {
+			return Bundle{}, fmt.Errorf("rucksack %v: item %q at %v isn't a letter", bundleNum+1, item, i+1)
+		}
+	}
+
+	halfway := length / 2
 
 	return Bundle{
 		full:      input,
-		start:     countItems(input[0 : halfway+1]),
-		end:       countItems(input[halfway+1:]),
-		all:       countItems(input),
+		start:     itemsOf(input[:halfway]),
+		end:       itemsOf(input[halfway:]),
+		all:       itemsOf(input),
 		bundleNum: bundleNum,
+	}, nil
+}
+
+func createBundles(lines []string) ([]Bundle, error) {
+	bundles := []Bundle{}
+	for i, line := range lines {
+		bundle, err := createBundle(line, i)
+		if err != nil {
+			return nil, err
+		}
+		bundles = append(bundles, bundle)
 	}
+
+	return bundles, nil
+}
+
+func isItem(item rune) bool {
+	return (item >= 'a' && item <= 'z') || (item >= 'A' && item <= 'Z')
 }
 
 // a - z is 1 - 26, and A - Z i 27 - 52
 func convertItemToValue(item rune) rune {
-	lowercaseBaseValue := rune('a') - 1
-	uppercaseBaseValue := rune('A') - 1 - 26 // Uppercase values are 26 above
+	switch {
+	case item >= 'a' && item <= 'z':
+		return item - 'a' + 1
+	case item >= 'A' && item <= 'Z':
+		return item - 'A' + 27
+	}
 
-	if item >= lowercaseBaseValue {
-		return item - lowercaseBaseValue
+	panic(fmt.Sprintf("%q is not an item", item))
+}
+
+// 1 - 26 is a - z, and 27 - 52 is A - Z
+func convertValueToItem(value rune) rune {
+	if value <= 26 {
+		return 'a' + value - 1
 	}
 
-	return item - uppercaseBaseValue
+	return 'A' + value - 27
 }
 
 func parts() {
 	input := readLines("./input.txt")
 
-	var bundles []Bundle
-
-	for i, value := range input {
-		bundles = append(bundles, createBundle(value, i))
+	bundles, err := createBundles(input)
+	if err != nil {
+		panic(err)
 	}
 
 	score := 0
 	for _, bundle := range bundles {
-		common := findFirstCommon(bundle)
-		// fmt.Println(
-		// 	"Common - ",
-		// 	common,
-		// 	", ",
-		// 	string(common),
-		// 	", value: ",
-		// 	convertItemToValue(common),
-		// )
-		score += int(convertItemToValue(common))
+		common := findCommon(bundle)
+		if err := checkShared(common, *strict); err != nil {
+			panic(fmt.Errorf("rucksack %v: %w", bundle.bundleNum+1, err))
+		}
+
+		logger.Verbosef(
+			"rucksack %v: %v items, %v | %v, shares %v, value %v",
+			bundle.bundleNum+1,
+			len(bundle.full),
+			bundle.full[:len(bundle.full)/2],
+			bundle.full[len(bundle.full)/2:],
+			string(common),
+			priorityOf(common),
+		)
+		score += priorityOf(common)
 	}
 
 	fmt.Println("Part 1 - ", score)
 
-	groups := 3
+	var groups []Group
+	if *regroup {
+		groups, err = findGrouping(bundles, *groupSize)
+	} else {
+		groups, err = groupInOrder(bundles, *groupSize, *strict)
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	if *badges {
+		printBadgeReport(os.Stdout, groups)
+	}
+
 	score = 0
-	for i := 0; i < len(bundles); i += groups {
-		common := findCommonInBundles(bundles[i : i+groups])
-		score += int(convertItemToValue(common))
+	for i, group := range groups {
+		logger.Tracef("group %v: %v", i+1, group.toString())
+		score += priorityOf(group.badges)
 	}
 
 	fmt.Println("Part 2 - ", score)
 }
 
+var (
+	groupSize = flag.Int("group-size", 3, "how many rucksacks are in each group")
+	badges    = flag.Bool("badges", false, "list each group's badge and its priority")
+	strict    = flag.Bool("strict", false, "fail unless rucksacks and groups share exactly one item")
+	regroup   = flag.Bool("regroup", false, "find groups sharing one item, for unordered input")
+
+	bench      = flag.Bool("bench", false, "time maps against bitsets on generated rucksacks and exit")
+	benchLines = flag.Int("bench-lines", 1000000, "how many rucksacks to generate")
+	generate   = flag.String("generate", "", "write generated rucksacks to this file and exit")
+	seed       = flag.Int64("seed", 1, "seed for generating rucksacks")
+)
+
 func main() {
+	logger.RegisterFlags()
+	flag.Parse()
+
+	if *generate != "" {
+		file, err := os.Create(*generate)
+		if err != nil {
+			panic(err)
+		}
+		defer file.Close()
+
+		if err := generateRucksacks(*benchLines, *seed, file); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	if *bench {
+		if err := runBenchmark(*benchLines, *seed, os.Stdout); err != nil {
+			panic(err)
+		}
+		return
+	}
+
 	parts()
 }
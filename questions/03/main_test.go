@@ -0,0 +1,134 @@
+package main
+
+import (
+	"testing"
+)
+
+var example = []string{
+	"vJrwpWtwJgWrhcsFMMfFFhFp",
+	"jqHRNqRjqzjGDLGLrsFMfFZSrLrFZsSL",
+	"PmmdzqPrVvPwwTWBwg",
+	"wMqvLMZHhHMvwLHjbvcjnnSBnvTQFn",
+	"ttgJtRGJQctTZtZT",
+	"CrZsJsPPZsGzwwsLwLmpwMDw",
+}
+
+func TestExample(t *testing.T) {
+	bundles, err := createBundles(example)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	score := 0
+	for _, bundle := range bundles {
+		common := findCommon(bundle)
+		if err := checkShared(common, true); err != nil {
+			t.Fatal(err)
+		}
+		score += priorityOf(common)
+	}
+	if score != 157 {
+		t.Errorf("part 1 got %v, want 157", score)
+	}
+
+	groups, err := groupInOrder(bundles, 3, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	badges := ""
+	score = 0
+	for _, group := range groups {
+		badges += string(group.badges)
+		score += priorityOf(group.badges)
+	}
+	if badges != "rZ" || score != 70 {
+		t.Errorf("part 2 got badges %v worth %v, want rZ worth 70", badges, score)
+	}
+}
+
+func TestSharedItemsAreSorted(t *testing.T) {
+	bundle, err := createBundle("BaCcBaCc", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Lowest priority first, no matter the order in the rucksack
+	if got := string(findCommon(bundle)); got != "acBC" {
+		t.Errorf("got %v, want acBC", got)
+	}
+
+	if err := checkShared(findCommon(bundle), false); err != nil {
+		t.Errorf("more than one shared item should be fine without strict: %v", err)
+	}
+	if err := checkShared(findCommon(bundle), true); err == nil {
+		t.Error("more than one shared item should fail with strict")
+	}
+	if err := checkShared(nil, false); err == nil {
+		t.Error("no shared items should always fail")
+	}
+}
+
+func TestBadRucksacks(t *testing.T) {
+	for _, input := range []string{"", "abc", "ab1c", "ab c"} {
+		if _, err := createBundles([]string{"aa", input}); err == nil {
+			t.Errorf("%q should be rejected", input)
+		}
+	}
+
+	bundles, err := createBundles(example[:4])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := groupInOrder(bundles, 3, false); err == nil {
+		t.Error("4 rucksacks shouldn't split into groups of 3")
+	}
+}
+
+func TestFindGroupingShuffled(t *testing.T) {
+	shuffled := []string{example[3], example[0], example[4], example[1], example[5], example[2]}
+	bundles, err := createBundles(shuffled)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups, err := findGrouping(bundles, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, group := range groups {
+		members := []Bundle{}
+		for _, member := range group.members {
+			members = append(members, bundles[member])
+		}
+
+		shared := findCommonInBundles(members)
+		if len(shared) != 1 || shared[0] != group.badges[0] {
+			t.Errorf("group %v shares %q, want just %q", group.members, string(shared), string(group.badges))
+		}
+	}
+}
+
+func TestFirstItem(t *testing.T) {
+	if got := itemsOf("BaCc").first(); got != 'a' {
+		t.Errorf("got %q, want 'a'", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("first on an empty set should panic")
+		}
+	}()
+	ItemSet(0).first()
+}
+
+// Nothing is shared, so there's no item for the bitsets to pick
+func TestScorersWithNothingShared(t *testing.T) {
+	rucksacks := []string{"abcd", "efgh", "ijkl"}
+	for _, s := range scorers {
+		if score, badges := s.scores(rucksacks); score != 0 || badges != 0 {
+			t.Errorf("%v: got %v and %v, want 0 and 0", s.name, score, badges)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"questions/01/main/pkg/interval"
+)
+
+// One elf's sections, and where it came from in the input
+type Assignment struct {
+	sections Range
+	groupNum int
+	// Which elf in the group, starting at 0
+	elf int
+}
+
+func (a Assignment) toString() string {
+	return fmt.Sprintf("line %v elf %v (%v)", a.groupNum+1, a.elf+1, a.sections)
+}
+
+func (g WorkerGroup) assignments() []Assignment {
+	assignments := []Assignment{}
+	for i, sections := range g.ranges {
+		assignments = append(assignments, Assignment{
+			sections: sections,
+			groupNum: g.groupNum,
+			elf:      i,
+		})
+	}
+
+	return assignments
+}
+
+type CoverageReport struct {
+	// Sections at least one elf is on
+	covered *interval.Set
+	// Sections with at least crowdedAt elves on them
+	crowded   *interval.Set
+	crowdedAt int
+	// Sections between the lowest and highest assigned that nobody is on
+	gaps *interval.Set
+	// Assignments from different lines that share a section
+	crossPairs [][2]Assignment
+}
+
+// Where an assignment starts, or where it has just ended
+type sweepEvent struct {
+	position   int
+	start      bool
+	assignment int
+}
+
+// Orders assignments by line, and then by elf
+func assignmentBefore(a Assignment, b Assignment) bool {
+	if a.groupNum != b.groupNum {
+		return a.groupNum < b.groupNum
+	}
+	return a.elf < b.elf
+}
+
+// Sweeps across the sections from lowest to highest, keeping track of how many elves are
+// on each one and which assignments are active, so every overlapping pair is found when
+// the later one starts
+func analyzeCoverage(groups []WorkerGroup, crowdedAt int) CoverageReport {
+	assignments := []Assignment{}
+	for _, group := range groups {
+		assignments = append(assignments, group.assignments()...)
+	}
+
+	events := []sweepEvent{}
+	for i, assignment := range assignments {
+		if assignment.sections.IsEmpty() {
+			continue
+		}
+
+		events = append(events,
+			sweepEvent{position: assignment.sections.Start, start: true, assignment: i},
+			// Ranges are inclusive, so it ends just after the last section
+			sweepEvent{position: assignment.sections.End + 1, start: false, assignment: i},
+		)
+	}
+
+	// Ends go first, since those ranges finished before this position
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].position != events[j].position {
+			return events[i].position < events[j].position
+		}
+		return !events[i].start && events[j].start
+	})
+
+	report := CoverageReport{
+		covered:   &interval.Set{},
+		crowded:   &interval.Set{},
+		crowdedAt: crowdedAt,
+		gaps:      &interval.Set{},
+	}
+
+	active := map[int]bool{}
+	for i, event := range events {
+		if event.start {
+			for other := range active {
+				first, second := assignments[other], assignments[event.assignment]
+				if first.groupNum == second.groupNum {
+					continue
+				}
+
+				// Earlier line first, so the report reads top to bottom
+				if assignmentBefore(second, first) {
+					first, second = second, first
+				}
+				report.crossPairs = append(report.crossPairs, [2]Assignment{first, second})
+			}
+			active[event.assignment] = true
+		} else {
+			delete(active, event.assignment)
+		}
+
+		// The number of elves stays the same until the next event
+		if i+1 < len(events) && events[i+1].position > event.position {
+			stretch := interval.New(event.position, events[i+1].position-1)
+			if len(active) > 0 {
+				report.covered.Add(stretch)
+			}
+
+			if len(active) >= crowdedAt {
+				report.crowded.Add(stretch)
+			}
+		}
+	}
+
+	if span, ok := report.covered.Span(); ok {
+		report.gaps = interval.NewSet(span).Subtract(report.covered)
+	}
+
+	// Map ordering isn't fixed, so sort to keep the output the same every run
+	sort.Slice(report.crossPairs, func(i, j int) bool {
+		a, b := report.crossPairs[i], report.crossPairs[j]
+		if a[0] != b[0] {
+			return assignmentBefore(a[0], b[0])
+		}
+		return assignmentBefore(a[1], b[1])
+	})
+
+	return report
+}
+
+func printCoverageReport(report CoverageReport, listPairs bool) {
+	span, _ := report.covered.Span()
+	fmt.Println("Sections covered - ", report.covered.Len(), "of span", span)
+	fmt.Println(
+		"Sections with", report.crowdedAt, "or more elves - ",
+		report.crowded.Len(),
+		report.crowded,
+	)
+	fmt.Println("Sections nobody covers - ", report.gaps.Len(), report.gaps)
+	fmt.Println("Overlapping pairs across lines - ", len(report.crossPairs))
+
+	if listPairs {
+		for _, pair := range report.crossPairs {
+			fmt.Println(pair[0].toString(), "overlaps", pair[1].toString())
+		}
+	}
+}
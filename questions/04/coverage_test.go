@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"questions/01/main/pkg/interval"
+)
+
+func TestAnalyzeCoverage(t *testing.T) {
+	groups, err := createGroups([]string{
+		"1-3,10-12",
+		"2-4,11-11",
+		// These two overlap, but they're on the same line so they aren't a cross pair
+		"20-21,21-22",
+		"1-1,30-30",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := analyzeCoverage(groups, 2)
+
+	sets := []struct {
+		name string
+		got  *interval.Set
+		want []interval.Interval
+	}{
+		{"covered", report.covered, []interval.Interval{
+			interval.New(1, 4), interval.New(10, 12), interval.New(20, 22), interval.New(30, 30),
+		}},
+		{"crowded", report.crowded, []interval.Interval{
+			interval.New(1, 3), interval.New(11, 11), interval.New(21, 21),
+		}},
+		{"gaps", report.gaps, []interval.Interval{
+			interval.New(5, 9), interval.New(13, 19), interval.New(23, 29),
+		}},
+	}
+
+	for _, set := range sets {
+		if got := set.got.Intervals(); !reflect.DeepEqual(got, set.want) {
+			t.Errorf("%v: got %v, want %v", set.name, got, set.want)
+		}
+	}
+
+	pairs := [][2]string{}
+	for _, pair := range report.crossPairs {
+		pairs = append(pairs, [2]string{pair[0].toString(), pair[1].toString()})
+	}
+
+	want := [][2]string{
+		{"line 1 elf 1 (1-3)", "line 2 elf 1 (2-4)"},
+		{"line 1 elf 1 (1-3)", "line 4 elf 1 (1-1)"},
+		{"line 1 elf 2 (10-12)", "line 2 elf 2 (11-11)"},
+	}
+	if !reflect.DeepEqual(pairs, want) {
+		t.Errorf("got cross pairs %v, want %v", pairs, want)
+	}
+}
@@ -1,20 +1,21 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+
+	"questions/01/main/pkg/interval"
+	"questions/01/main/pkg/logger"
 )
 
-type Range struct {
-	start int
-	end   int
-}
+type Range = interval.Interval
 
+// All the elves on one line of the input. Usually a pair, but can be any number
 type WorkerGroup struct {
-	first    Range
-	second   Range
+	ranges   []Range
 	groupNum int
 }
 
@@ -27,82 +28,150 @@ func readLines(inputFile string) []string {
 	return strings.Split(string(dat), "\n")
 }
 
-// Creates a range from a number like 1-4
-func createRange(str string) Range {
-	strSplit := strings.Split(str, "-")
+// Creates a range from a number like 1-4. Backwards ranges are an error rather than
+// guessing which end was meant
+func createRange(str string) (Range, error) {
+	startStr, endStr, found := strings.Cut(str, "-")
+	if !found {
+		return Range{}, fmt.Errorf("range %q should look like 1-4", str)
+	}
 
-	start, err := strconv.Atoi(strSplit[0])
+	start, err := strconv.Atoi(startStr)
 	if err != nil {
-		panic(err)
+		return Range{}, fmt.Errorf("range %q: start isn't a number", str)
 	}
 
-	end, err := strconv.Atoi(strSplit[1])
+	end, err := strconv.Atoi(endStr)
 	if err != nil {
-		panic(err)
+		return Range{}, fmt.Errorf("range %q: end isn't a number", str)
 	}
 
-	return Range{
-		start: start,
-		end:   end,
+	if end < start {
+		return Range{}, fmt.Errorf("range %q ends before it starts", str)
 	}
+
+	return interval.New(start, end), nil
 }
 
-func createGroup(input string, groupNum int) WorkerGroup {
+func createGroup(input string, groupNum int) (WorkerGroup, error) {
 	ranges := strings.Split(input, ",")
-	fmt.Println(ranges)
+	logger.Verbosef("line %v: %v", groupNum+1, ranges)
+
+	group := WorkerGroup{groupNum: groupNum}
+	for _, r := range ranges {
+		created, err := createRange(r)
+		if err != nil {
+			// Line numbers are 1 indexed to match an editor
+			return WorkerGroup{}, fmt.Errorf("line %v: %w", groupNum+1, err)
+		}
+		group.ranges = append(group.ranges, created)
+	}
+
+	return group, nil
+}
 
-	return WorkerGroup{
-		first:    createRange(ranges[0]),
-		second:   createRange(ranges[1]),
-		groupNum: groupNum,
+func createGroups(input []string) ([]WorkerGroup, error) {
+	groups := []WorkerGroup{}
+	for i, value := range input {
+		group, err := createGroup(value, i)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
 	}
+
+	return groups, nil
 }
 
-// 1. Partial overlap where first.start is less than second.end
-// 2. Partial overlap where second.start is less than first.end
-// 3. Full overlap where first is contained by second
-// 4. Full overlap where second is contained by first
-func findOverlap(group WorkerGroup, fullOverlapOnly bool) *Range {
-	if !fullOverlapOnly && group.second.start <= group.first.start &&
-		group.first.start <= group.second.end {
-		return &Range{
-			start: group.first.start,
-			end:   group.second.end,
+// The sections every elf in the group was assigned, or false if there aren't any
+func (g WorkerGroup) commonSections() (Range, bool) {
+	if len(g.ranges) == 0 {
+		return Range{}, false
+	}
+
+	common := g.ranges[0]
+	for _, r := range g.ranges[1:] {
+		intersection, ok := common.Intersect(r)
+		if !ok {
+			return Range{}, false
 		}
-	} else if !fullOverlapOnly && group.first.start <= group.second.start && group.second.start <= group.first.end {
-		return &Range{
-			start: group.second.start,
-			end:   group.first.end,
+		common = intersection
+	}
+
+	return common, true
+}
+
+// Index of an elf whose range contains everyone else's, or -1 if nobody's does
+func (g WorkerGroup) findContainer() int {
+	for i, outer := range g.ranges {
+		containsAll := true
+		for _, inner := range g.ranges {
+			if !outer.Contains(inner) {
+				containsAll = false
+				break
+			}
+		}
+
+		if containsAll {
+			return i
 		}
-	} else if group.first.start >= group.second.start && group.first.end <= group.second.end {
-		return &group.first
-	} else if group.second.start >= group.first.start && group.second.end <= group.first.end {
-		return &group.second
 	}
 
-	return nil
+	return -1
 }
 
-func findCompleteOverlap(group WorkerGroup) *Range {
-	return findOverlap(group, true)
+// For each elf, the other elves whose ranges fit inside theirs.
+// Elves with the same range contain each other
+func (g WorkerGroup) containmentGraph() [][]int {
+	graph := make([][]int, len(g.ranges))
+	for i, outer := range g.ranges {
+		graph[i] = []int{}
+		for j, inner := range g.ranges {
+			if i != j && outer.Contains(inner) {
+				graph[i] = append(graph[i], j)
+			}
+		}
+	}
+
+	return graph
 }
 
-func findPartialOverlap(group WorkerGroup) *Range {
-	return findOverlap(group, false)
+func (g WorkerGroup) toString() string {
+	ranges := []string{}
+	for _, r := range g.ranges {
+		ranges = append(ranges, r.String())
+	}
+
+	return strings.Join(ranges, ",")
 }
 
 func parts() {
-	input := readLines("./input.txt")
+	groups, err := createGroups(readLines("./input.txt"))
+	if err != nil {
+		panic(err)
+	}
 
 	overlapFull := 0
 	overlapPartial := 0
-	for i, value := range input {
-		newGroup := createGroup(value, i)
-		if findCompleteOverlap(newGroup) != nil {
+	for _, newGroup := range groups {
+		contains := newGroup.findContainer() >= 0
+		_, shares := newGroup.commonSections()
+		if logger.Enabled(logger.Trace) {
+			logger.Tracef(
+				"line %v: contains all %v, shares sections %v",
+				newGroup.groupNum+1,
+				contains,
+				findCommonString(newGroup),
+			)
+		}
+
+		// Someone's range covers everyone else's
+		if contains {
 			overlapFull += 1
 		}
 
-		if findPartialOverlap(newGroup) != nil {
+		// Everyone shares at least one section
+		if shares {
 			overlapPartial += 1
 		}
 	}
@@ -111,6 +180,69 @@ func parts() {
 	fmt.Println("Part 2 - ", overlapPartial)
 }
 
+var (
+	coverage  = flag.Bool("coverage", false, "analyze section coverage across every line")
+	crowdedAt = flag.Int("crowded", 3, "how many elves on a section counts as crowded")
+	listPairs = flag.Bool("pairs", false, "list every overlapping pair with -coverage")
+	report    = flag.Bool("report", false, "print the overlaps and containment for every line")
+)
+
+// The shared sections like "3-5", or "none"
+func findCommonString(group WorkerGroup) string {
+	if overlap, ok := group.commonSections(); ok {
+		return overlap.String()
+	}
+
+	return "none"
+}
+
+// Prints what every group shares, and who contains who. Elves are numbered from 1
+func printGroupReport(groups []WorkerGroup) {
+	for _, group := range groups {
+		common := findCommonString(group)
+
+		container := "none"
+		if elf := group.findContainer(); elf >= 0 {
+			container = fmt.Sprint("elf ", elf+1)
+		}
+
+		edges := []string{}
+		for outer, inners := range group.containmentGraph() {
+			for _, inner := range inners {
+				edges = append(edges, fmt.Sprintf("%v>%v", outer+1, inner+1))
+			}
+		}
+
+		fmt.Printf(
+			"line %v (%v): common %v, contains all %v, contains [%v]\n",
+			group.groupNum+1,
+			group.toString(),
+			common,
+			container,
+			strings.Join(edges, " "),
+		)
+	}
+}
+
 func main() {
+	logger.RegisterFlags()
+	flag.Parse()
+
+	if *coverage || *report {
+		groups, err := createGroups(readLines("./input.txt"))
+		if err != nil {
+			panic(err)
+		}
+
+		if *report {
+			printGroupReport(groups)
+		}
+
+		if *coverage {
+			printCoverageReport(analyzeCoverage(groups, *crowdedAt), *listPairs)
+		}
+		return
+	}
+
 	parts()
 }
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExampleOverlaps(t *testing.T) {
+	example := []string{
+		"2-4,6-8",
+		"2-3,4-5",
+		"5-7,7-9",
+		"2-8,3-7",
+		"6-6,4-6",
+		"2-6,4-8",
+	}
+
+	groups, err := createGroups(example)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contained, overlapping := 0, 0
+	for _, group := range groups {
+		if group.findContainer() >= 0 {
+			contained += 1
+		}
+		if _, ok := group.commonSections(); ok {
+			overlapping += 1
+		}
+	}
+
+	if contained != 2 || overlapping != 4 {
+		t.Errorf("got %v contained and %v overlapping, want 2 and 4", contained, overlapping)
+	}
+}
+
+func TestMalformedRanges(t *testing.T) {
+	for _, input := range []string{"5-3,1-2", "7", "", "1-2,a-4", "1-2,3-"} {
+		_, err := createGroups([]string{"1-1,1-1", input})
+		if err == nil || !strings.HasPrefix(err.Error(), "line 2: ") {
+			t.Errorf("%q: got error %v, want a line 2 error", input, err)
+		}
+	}
+}
+
+func TestMultiRangeGroups(t *testing.T) {
+	tests := []struct {
+		input     string
+		common    string
+		container int
+		graph     [][]int
+	}{
+		{"2-8,3-7,4-5", "4-5", 0, [][]int{{1, 2}, {2}, {}}},
+		{"2-4,6-8,3-3", "", -1, [][]int{{2}, {}, {}}},
+		{"1-1,1-1", "1-1", 0, [][]int{{1}, {0}}},
+	}
+
+	for _, test := range tests {
+		group, err := createGroup(test.input, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		common := ""
+		if sections, ok := group.commonSections(); ok {
+			common = sections.String()
+		}
+		if common != test.common {
+			t.Errorf("%v: got common sections %q, want %q", test.input, common, test.common)
+		}
+
+		if got := group.findContainer(); got != test.container {
+			t.Errorf("%v: got container %v, want %v", test.input, got, test.container)
+		}
+
+		if got := group.containmentGraph(); !reflect.DeepEqual(got, test.graph) {
+			t.Errorf("%v: got containment %v, want %v", test.input, got, test.graph)
+		}
+	}
+}
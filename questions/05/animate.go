@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// The stacks after a single instruction
+type Frame struct {
+	// Index of the instruction that was just run, or -1 for the starting stacks
+	index       int
+	instruction Instruction
+	stacks      []Stack
+	// How many crates were just put on top of the destination
+	moved int
+}
+
+func (f Frame) isMoved(stackIndex int, height int) bool {
+	if f.index < 0 || stackIndex != f.instruction.destination {
+		return false
+	}
+
+	return height >= len(f.stacks[stackIndex])-f.moved
+}
+
+// The crates that were just moved, top first
+func (f Frame) movedCrates() []string {
+	crates := []string{}
+	if f.index < 0 {
+		return crates
+	}
+
+	destination := f.stacks[f.instruction.destination]
+	for i := len(destination) - 1; i >= len(destination)-f.moved; i -= 1 {
+		crates = append(crates, destination[i])
+	}
+
+	return crates
+}
+
+func (f Frame) toString(decorate CellDecorator) string {
+	if f.index < 0 {
+		return "start\n" + renderStacksWith(f.stacks, decorate)
+	}
+
+	return fmt.Sprintf(
+		"%v - %v\n%v\nmoved %v onto stack %v",
+		f.index+1,
+		f.instruction.toString(),
+		renderStacksWith(f.stacks, decorate),
+		strings.Join(f.movedCrates(), ", "),
+		f.instruction.destination+1,
+	)
+}
+
+// Runs the instructions and keeps a frame of the stacks after each one, starting with the
+// stacks as they are. If an instruction fails, the frames up to it are still returned
+func recordFrames(stacks []Stack, instructions []Instruction, crane Crane, strict bool) (
+	[]Frame,
+	error,
+) {
+	frames := []Frame{{index: -1, stacks: copyStacks(stacks)}}
+
+	err := runInstructions(
+		stacks,
+		instructions,
+		crane,
+		strict,
+		func(index int, instruction Instruction, stacks []Stack) {
+			before := frames[len(frames)-1].stacks[instruction.destination]
+			frames = append(frames, Frame{
+				index:       index,
+				instruction: instruction,
+				stacks:      copyStacks(stacks),
+				moved:       len(stacks[instruction.destination]) - len(before),
+			})
+		},
+	)
+
+	return frames, err
+}
+
+const (
+	clearScreen = "\033[H\033[2J"
+	highlight   = "\033[1;33m"
+	resetColor  = "\033[0m"
+)
+
+// Plays the frames in the terminal, with the moved crates highlighted
+func playFrames(frames []Frame, delay time.Duration, w io.Writer) {
+	for i, frame := range frames {
+		decorate := func(stackIndex int, height int, cell string) string {
+			if frame.isMoved(stackIndex, height) {
+				return highlight + cell + resetColor
+			}
+			return cell
+		}
+
+		fmt.Fprint(w, clearScreen)
+		fmt.Fprintln(w, frame.toString(decorate))
+
+		if i < len(frames)-1 {
+			time.Sleep(delay)
+		}
+	}
+}
+
+// Every frame as plain text, one after another
+func writeFrameLog(frames []Frame, w io.Writer) error {
+	for _, frame := range frames {
+		if _, err := fmt.Fprintf(w, "%v\n\n", frame.toString(nil)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Palette indexes for the GIF. Crate colors come after these
+const (
+	backgroundIndex uint8 = iota
+	floorIndex
+	highlightIndex
+	firstCrateIndex
+)
+
+var gifPalette = color.Palette{
+	color.RGBA{R: 0x1e, G: 0x1e, B: 0x1e, A: 0xff},
+	color.RGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	color.RGBA{R: 0xff, G: 0xd7, B: 0x00, A: 0xff},
+	color.RGBA{R: 0x8b, G: 0x5a, B: 0x2b, A: 0xff},
+	color.RGBA{R: 0xa0, G: 0x52, B: 0x2d, A: 0xff},
+	color.RGBA{R: 0xcd, G: 0x85, B: 0x3f, A: 0xff},
+	color.RGBA{R: 0xd2, G: 0x69, B: 0x1e, A: 0xff},
+	color.RGBA{R: 0x6b, G: 0x8e, B: 0x23, A: 0xff},
+	color.RGBA{R: 0x46, G: 0x82, B: 0xb4, A: 0xff},
+	color.RGBA{R: 0x70, G: 0x80, B: 0x90, A: 0xff},
+	color.RGBA{R: 0x8f, G: 0xbc, B: 0x8f, A: 0xff},
+	color.RGBA{R: 0xbc, G: 0x8f, B: 0x8f, A: 0xff},
+	color.RGBA{R: 0x9a, G: 0xcd, B: 0x32, A: 0xff},
+	color.RGBA{R: 0x5f, G: 0x9e, B: 0xa0, A: 0xff},
+	color.RGBA{R: 0xb0, G: 0x30, B: 0x60, A: 0xff},
+}
+
+// The same label always gets the same color
+func crateColorIndex(label string) uint8 {
+	hash := fnv.New32a()
+	hash.Write([]byte(label))
+	crateColors := uint32(len(gifPalette)) - uint32(firstCrateIndex)
+	return firstCrateIndex + uint8(hash.Sum32()%crateColors)
+}
+
+func fillRect(img *image.Paletted, rect image.Rectangle, index uint8) {
+	for y := rect.Min.Y; y < rect.Max.Y; y += 1 {
+		for x := rect.Min.X; x < rect.Max.X; x += 1 {
+			img.SetColorIndex(x, y, index)
+		}
+	}
+}
+
+// Draws each crate as a colored square, with the ones that were just moved outlined.
+// All frames are drawn at the same size, so it fits the tallest stack in any of them
+func writeFrameGIF(frames []Frame, cellSize int, delay time.Duration, w io.Writer) error {
+	gap := 2
+	floorHeight := 2
+
+	tallest := 0
+	for _, frame := range frames {
+		for _, stack := range frame.stacks {
+			if len(stack) > tallest {
+				tallest = len(stack)
+			}
+		}
+	}
+
+	totalStacks := len(frames[0].stacks)
+	width := totalStacks*(cellSize+gap) + gap
+	height := tallest*(cellSize+gap) + gap + floorHeight
+	floorTop := height - floorHeight
+
+	animation := &gif.GIF{}
+	for _, frame := range frames {
+		img := image.NewPaletted(image.Rect(0, 0, width, height), gifPalette)
+		fillRect(img, image.Rect(0, floorTop, width, height), floorIndex)
+
+		for stackIndex, stack := range frame.stacks {
+			left := gap + stackIndex*(cellSize+gap)
+			for crateHeight, crate := range stack {
+				bottom := floorTop - crateHeight*(cellSize+gap)
+				cell := image.Rect(left, bottom-cellSize, left+cellSize, bottom)
+
+				if frame.isMoved(stackIndex, crateHeight) {
+					fillRect(img, cell, highlightIndex)
+					cell = cell.Inset(2)
+				}
+				fillRect(img, cell, crateColorIndex(crate))
+			}
+		}
+
+		animation.Image = append(animation.Image, img)
+		animation.Delay = append(animation.Delay, int(delay.Milliseconds()/10))
+	}
+
+	return gif.EncodeAll(w, animation)
+}
+
+// Creates the file and hands it to write
+func writeFile(filename string, write func(w io.Writer) error) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
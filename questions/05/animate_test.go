@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecordFrames(t *testing.T) {
+	frames, err := recordFrames(exampleStacks(t), parseMoves(t, exampleMoves), CrateMover9000{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The starting stacks and then one per move. Moved crates are listed top first
+	want := [][]string{{}, {"D"}, {"Z", "N", "D"}, {"M", "C"}, {"M"}}
+	if len(frames) != len(want) {
+		t.Fatalf("got %v frames, want %v", len(frames), len(want))
+	}
+
+	for i, frame := range frames {
+		if frame.index != i-1 {
+			t.Errorf("frame %v has index %v", i, frame.index)
+		}
+		if got := frame.movedCrates(); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("frame %v moved %v, want %v", i, got, want[i])
+		}
+	}
+
+	// move 3 from 1 to 3 left P D N Z, and only the top three were moved
+	for height, moved := range []bool{false, true, true, true} {
+		if frames[2].isMoved(2, height) != moved {
+			t.Errorf("stack 3 height %v: isMoved should be %v", height, moved)
+		}
+	}
+	if frames[2].isMoved(0, 0) || frames[0].isMoved(0, 0) {
+		t.Error("only the destination of a move is highlighted")
+	}
+
+	out := &bytes.Buffer{}
+	if err := writeFrameLog(frames, out); err != nil {
+		t.Fatal(err)
+	}
+
+	log := out.String()
+	if !strings.HasPrefix(log, "start\n"+exampleDrawing+"\n\n") {
+		t.Errorf("log doesn't start with the starting stacks:\n%v", log)
+	}
+	if !strings.Contains(log, "2 - move 3 from 1 to 3\n") ||
+		!strings.Contains(log, "moved Z, N, D onto stack 3\n\n") {
+		t.Errorf("log is missing the second move:\n%v", log)
+	}
+	if strings.Count(log, "\n\n") != len(frames) {
+		t.Errorf("log should have %v frames:\n%v", len(frames), log)
+	}
+}
+
+func TestRecordFramesStopsAtBadMove(t *testing.T) {
+	moves := parseMoves(t, []string{"move 1 from 2 to 1", "move 5 from 2 to 3", "move 1 from 1 to 2"})
+	frames, err := recordFrames(exampleStacks(t), moves, CrateMover9000{}, true)
+
+	var moveErr *MoveError
+	if !errors.As(err, &moveErr) || moveErr.index != 1 {
+		t.Fatalf("got %v, want a MoveError on the second move", err)
+	}
+
+	// The start and the one move that worked
+	if len(frames) != 2 || !reflect.DeepEqual(frames[1].movedCrates(), []string{"D"}) {
+		t.Errorf("got %v frames", len(frames))
+	}
+}
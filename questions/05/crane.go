@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Moves crates between stacks. The caller makes sure there are at least total
+// crates on the source stack
+type Crane interface {
+	move(source *Stack, destination *Stack, total int)
+}
+
+// Moves one crate at a time, so a group ends up in reverse order
+type CrateMover9000 struct{}
+
+func (c CrateMover9000) move(source *Stack, destination *Stack, total int) {
+	for i := 0; i < total; i += 1 {
+		destination.Push(source.Pop())
+	}
+}
+
+// Moves all the crates at once, so a group keeps its order
+type CrateMover9001 struct{}
+
+func (c CrateMover9001) move(source *Stack, destination *Stack, total int) {
+	destination.PushGroup(source.PopGroup(total))
+}
+
+// Moves crates in groups, but can only lift so many at a time.
+// A capacity of 1 is the same as the CrateMover 9000
+type CapacityCrane struct {
+	capacity int
+}
+
+func (c CapacityCrane) move(source *Stack, destination *Stack, total int) {
+	for remaining := total; remaining > 0; remaining -= c.capacity {
+		destination.PushGroup(source.PopGroup(min(c.capacity, remaining)))
+	}
+}
+
+// Pulls crates out from the bottom of the source, one at a time, and puts them on top
+// of the destination
+type BottomCrane struct{}
+
+func (c BottomCrane) move(source *Stack, destination *Stack, total int) {
+	for i := 0; i < total; i += 1 {
+		destination.Push(source.PopBottom())
+	}
+}
+
+// Capacity only applies to the capacity crane
+var craneModels = map[string]func(capacity int) Crane{
+	"9000":     func(int) Crane { return CrateMover9000{} },
+	"9001":     func(int) Crane { return CrateMover9001{} },
+	"capacity": func(capacity int) Crane { return CapacityCrane{capacity: capacity} },
+	"bottom":   func(int) Crane { return BottomCrane{} },
+}
+
+func craneNames() string {
+	names := []string{}
+	for name := range craneModels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
+
+func newCrane(name string, capacity int) (Crane, error) {
+	create, exists := craneModels[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown crane %q, pick one of %v", name, craneNames())
+	}
+
+	if capacity < 1 {
+		return nil, fmt.Errorf("crane capacity %v should be at least 1", capacity)
+	}
+
+	return create(capacity), nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestCraneModels(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		want     string
+	}{
+		{"9000", 3, "ZNDCM"},
+		{"9001", 3, "ZNMCD"},
+		{"capacity", 2, "ZNCDM"},
+		{"capacity", 1, "ZNDCM"},
+		{"bottom", 3, "ZNMCD"},
+	}
+
+	for _, test := range tests {
+		crane, err := newCrane(test.name, test.capacity)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// move 3 from 2 to 1 on the example
+		stacks := exampleStacks(t)
+		crane.move(&stacks[1], &stacks[0], 3)
+
+		got := ""
+		for _, crate := range stacks[0] {
+			got += crate
+		}
+		if got != test.want || !stacks[1].IsEmpty() {
+			t.Errorf("%v (capacity %v): got %v, want %v", test.name, test.capacity, got, test.want)
+		}
+	}
+}
+
+func TestNewCraneErrors(t *testing.T) {
+	if _, err := newCrane("9002", 3); err == nil {
+		t.Error("an unknown crane should be an error")
+	}
+
+	for _, capacity := range []int{0, -1} {
+		if _, err := newCrane("capacity", capacity); err == nil {
+			t.Errorf("capacity %v should be an error", capacity)
+		}
+	}
+}
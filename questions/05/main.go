@@ -1,11 +1,16 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
+
+	"questions/01/main/pkg/collections"
 )
 
 type QVariant int64
@@ -15,51 +20,7 @@ const (
 	Part2 QVariant = 2
 )
 
-type Stack []string
-
-// Push a single item onto the stack
-func (s *Stack) Push(v string) Stack {
-	*s = append(*s, v)
-	return *s
-}
-
-// Push group onto stack "in order". So the whole
-// stack is simply insterted on top
-func (s *Stack) PushGroup(v Stack) Stack {
-	*s = append(*s, v...)
-	return *s
-}
-
-// Pops a single item from the stack
-func (s *Stack) Pop() string {
-	last := len(*s) - 1
-	value := (*s)[last]
-	*s = (*s)[:last]
-	return value
-}
-
-// Pops a group of items, keeping their order.
-// So if the total is 3, it will take the top 3 items off
-// and maintain their order
-func (s *Stack) PopGroup(total int) Stack {
-	length := len(*s)
-	values := (*s)[length-total:]
-	*s = (*s)[:length-total]
-
-	return values
-}
-
-func (s *Stack) IsEmpty() bool {
-	return len(*s) == 0
-}
-
-func (s *Stack) Peek() string {
-	if s.IsEmpty() {
-		panic("Stack is empty. cannot peek")
-	}
-
-	return (*s)[len(*s)-1]
-}
+type Stack = collections.Stack[string]
 
 func min(a int, b int) int {
 	if a < b {
@@ -75,6 +36,11 @@ type Instruction struct {
 	total       int
 }
 
+// Back into the input format, where stacks start at 1
+func (i Instruction) toString() string {
+	return fmt.Sprintf("move %v from %v to %v", i.total, i.source+1, i.destination+1)
+}
+
 var instructionRegex, _ = regexp.Compile(`move ([0-9]+) from ([0-9]+) to ([0-9]+)`)
 
 func readLines(inputFile string) []string {
@@ -95,48 +61,158 @@ func parseToIntOrPanic(s string) int {
 	return i
 }
 
+// Where a stack's number sits in the legend line, as [start, end)
+type LegendColumn struct {
+	start int
+	end   int
+}
+
+// Finds each stack number in the legend. The stacks have to be numbered 1, 2, 3...
+// but can be any width apart
+func parseLegend(legend string) ([]LegendColumn, error) {
+	columns := []LegendColumn{}
+	for i := 0; i < len(legend); {
+		if legend[i] == ' ' {
+			i += 1
+			continue
+		}
+
+		end := i
+		for end < len(legend) && legend[end] != ' ' {
+			end += 1
+		}
+
+		number, err := strconv.Atoi(legend[i:end])
+		if err != nil {
+			return nil, fmt.Errorf("legend has %q, which isn't a stack number", legend[i:end])
+		}
+
+		if number != len(columns)+1 {
+			return nil, fmt.Errorf("legend has stack %v where stack %v should be", number, len(columns)+1)
+		}
+
+		columns = append(columns, LegendColumn{start: i, end: end})
+		i = end
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("legend has no stack numbers")
+	}
+
+	return columns, nil
+}
+
+// Finds the stack whose legend number sits under the crate's brackets
+func findColumn(columns []LegendColumn, open int, close int) (int, error) {
+	found := -1
+	for i, column := range columns {
+		if open < column.end && column.start <= close {
+			if found >= 0 {
+				return -1, fmt.Errorf("crate spans stacks %v and %v", found+1, i+1)
+			}
+			found = i
+		}
+	}
+
+	if found < 0 {
+		return -1, fmt.Errorf("crate isn't above any stack number")
+	}
+
+	return found, nil
+}
+
 // Rows come in with the legend on the bottom
 // Let's reverse the list, and start from the bottom.
-func parseRows(rows []string) []Stack {
-
+// Crates are matched to whichever stack number they sit over, so labels can be
+// any width. Errors have the 1 indexed line and column of the bad crate
+func parseRows(rows []string) ([]Stack, error) {
 	length := len(rows)
-	legend := strings.Trim(rows[length-1], " ")
+	if length == 0 {
+		return nil, fmt.Errorf("no stack drawing found")
+	}
 
-	colNames := strings.Split(legend, " ")
-	totalStacks, err := strconv.Atoi(colNames[len(colNames)-1])
+	columns, err := parseLegend(rows[length-1])
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("line %v: %w", length, err)
 	}
 
-	stacks := []Stack{}
-	for i := 0; i < totalStacks; i += 1 {
-		stacks = append(stacks, Stack{})
+	stacks := make([]Stack, len(columns))
+	for i := range stacks {
+		stacks[i] = Stack{}
 	}
 
-	// Every column is always a width of 3, and then space delimited after
-	colWidth := 3
 	for rowIndex := length - 2; rowIndex >= 0; rowIndex -= 1 {
 		row := rows[rowIndex]
+		height := length - 1 - rowIndex
+
+		for colIndex := 0; colIndex < len(row); {
+			if row[colIndex] == ' ' {
+				colIndex += 1
+				continue
+			}
+
+			if row[colIndex] != '[' {
+				return nil, fmt.Errorf(
+					"line %v, column %v: expected a crate, found %q",
+					rowIndex+1,
+					colIndex+1,
+					row[colIndex],
+				)
+			}
+
+			closeIndex := strings.IndexByte(row[colIndex:], ']')
+			if closeIndex < 0 {
+				return nil, fmt.Errorf(
+					"line %v, column %v: crate is missing its ]",
+					rowIndex+1,
+					colIndex+1,
+				)
+			}
+			closeIndex += colIndex
+
+			label := row[colIndex+1 : closeIndex]
+			if label == "" || strings.ContainsAny(label, "[ ") {
+				return nil, fmt.Errorf(
+					"line %v, column %v: crate label %q isn't valid",
+					rowIndex+1,
+					colIndex+1,
+					label,
+				)
+			}
 
-		for colIndex, stackIndex := 0, 0; colIndex < len(row); colIndex, stackIndex = colIndex+colWidth, stackIndex+1 {
-			value := row[colIndex : colIndex+colWidth]
-			if value[0] == '[' {
-				stacks[stackIndex] = append(stacks[stackIndex], string(value[1]))
+			stackIndex, err := findColumn(columns, colIndex, closeIndex)
+			if err != nil {
+				return nil, fmt.Errorf("line %v, column %v: %w", rowIndex+1, colIndex+1, err)
 			}
 
-			// Assume space for column
-			colIndex += 1
+			// Two crates on the same stack in a row, or one floating over a gap
+			if len(stacks[stackIndex]) != height-1 {
+				return nil, fmt.Errorf(
+					"line %v, column %v: crate %v isn't sitting on top of stack %v",
+					rowIndex+1,
+					colIndex+1,
+					label,
+					stackIndex+1,
+				)
+			}
+
+			stacks[stackIndex] = append(stacks[stackIndex], label)
+			colIndex = closeIndex + 1
 		}
 	}
 
-	return stacks
+	return stacks, nil
 }
 
-func parseInstructions(instructionsInput []string) []Instruction {
+func parseInstructions(instructionsInput []string) ([]Instruction, error) {
 	instructions := []Instruction{}
-	for _, input := range instructionsInput {
+	for i, input := range instructionsInput {
 		// This returns the original string as match 0
 		matches := instructionRegex.FindStringSubmatch(input)
+		if matches == nil {
+			return nil, fmt.Errorf("instruction %v: %q isn't a move", i+1, input)
+		}
+
 		instructions = append(instructions, Instruction{
 			source: parseToIntOrPanic(
 				matches[2],
@@ -146,11 +222,11 @@ func parseInstructions(instructionsInput []string) []Instruction {
 		})
 	}
 
-	return instructions
+	return instructions, nil
 }
 
 // Returns all the stacks, and instructions
-func parseInput(filename string) ([]Stack, []Instruction) {
+func parseInput(filename string) ([]Stack, []Instruction, error) {
 	input := readLines(filename)
 
 	// It'll be easier to start bottom to top to parse the row
@@ -169,36 +245,128 @@ func parseInput(filename string) ([]Stack, []Instruction) {
 		}
 	}
 
-	return parseRows(rowsInput), parseInstructions(instructionsInput)
+	stacks, err := parseRows(rowsInput)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	instructions, err := parseInstructions(instructionsInput)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return stacks, instructions, nil
 }
 
-func runInstructions(stacks []Stack, instructions []Instruction, variant QVariant) {
-	for _, instruction := range instructions {
-		sourceStack := stacks[instruction.source]
-		destStack := stacks[instruction.destination]
-		if variant == Part1 {
-			for i := 0; i < instruction.total; i += 1 {
-				if !sourceStack.IsEmpty() {
-					destStack.Push(sourceStack.Pop())
-				}
-			}
-		} else {
-			if !sourceStack.IsEmpty() {
-				totalToTake := min(instruction.total, len(sourceStack))
-				destStack.PushGroup(sourceStack.PopGroup(totalToTake))
+// An instruction that can't be carried out, along with the stacks as they were before it
+type MoveError struct {
+	index       int
+	instruction Instruction
+	stacks      []Stack
+	reason      string
+}
+
+func (e *MoveError) Error() string {
+	return fmt.Sprintf(
+		"instruction %v (%v): %v\n%v",
+		e.index+1,
+		e.instruction.toString(),
+		e.reason,
+		renderStacks(e.stacks),
+	)
+}
+
+// Copies every stack, so they can't change underneath us
+func copyStacks(stacks []Stack) []Stack {
+	copied := make([]Stack, len(stacks))
+	for i, stack := range stacks {
+		copied[i] = append(Stack{}, stack...)
+	}
+
+	return copied
+}
+
+// Returns why the instruction can't be run, or "" if it can.
+// In strict mode, moves that can only be partly done aren't allowed either
+func checkInstruction(stacks []Stack, instruction Instruction, strict bool) string {
+	if instruction.source < 0 || instruction.source >= len(stacks) {
+		return fmt.Sprintf("there's no stack %v", instruction.source+1)
+	}
+
+	if instruction.destination < 0 || instruction.destination >= len(stacks) {
+		return fmt.Sprintf("there's no stack %v", instruction.destination+1)
+	}
+
+	if !strict {
+		return ""
+	}
+
+	if instruction.source == instruction.destination {
+		return "source and destination are the same stack"
+	}
+
+	if instruction.total > len(stacks[instruction.source]) {
+		return fmt.Sprintf(
+			"can't move %v crates, stack %v only has %v",
+			instruction.total,
+			instruction.source+1,
+			len(stacks[instruction.source]),
+		)
+	}
+
+	return ""
+}
+
+// Called after each instruction has run, with the stacks as they are now
+type MoveHandler func(index int, instruction Instruction, stacks []Stack)
+
+// Stops at the first instruction that can't be run. Outside of strict mode, moves
+// onto the same stack are skipped and moving too many crates moves what's there.
+// onMove can be nil
+func runInstructions(
+	stacks []Stack,
+	instructions []Instruction,
+	crane Crane,
+	strict bool,
+	onMove MoveHandler,
+) error {
+	for index, instruction := range instructions {
+		if reason := checkInstruction(stacks, instruction, strict); reason != "" {
+			return &MoveError{
+				index:       index,
+				instruction: instruction,
+				stacks:      copyStacks(stacks),
+				reason:      reason,
 			}
 		}
 
-		stacks[instruction.source] = sourceStack
-		stacks[instruction.destination] = destStack
+		if instruction.source != instruction.destination {
+			sourceStack := stacks[instruction.source]
+			destStack := stacks[instruction.destination]
+
+			// Take whatever's left if there aren't enough crates
+			totalToTake := min(instruction.total, len(sourceStack))
+			crane.move(&sourceStack, &destStack, totalToTake)
+
+			stacks[instruction.source] = sourceStack
+			stacks[instruction.destination] = destStack
+		}
+
+		if onMove != nil {
+			onMove(index, instruction, stacks)
+		}
 	}
 
+	return nil
 }
 
-func parts(variant QVariant) {
-	stacks, instructions := parseInput("./input.txt")
+// Which crane each part of the puzzle uses
+var partCranes = map[QVariant]Crane{
+	Part1: CrateMover9000{},
+	Part2: CrateMover9001{},
+}
 
-	runInstructions(stacks, instructions, variant)
+func topCrates(stacks []Stack) string {
 	tops := ""
 	for _, stack := range stacks {
 		if !stack.IsEmpty() {
@@ -206,10 +374,182 @@ func parts(variant QVariant) {
 		}
 	}
 
+	return tops
+}
+
+type RunOptions struct {
+	// Draw the stacks once all the moves are done
+	render bool
+	// Draw the stacks after every move
+	renderSteps bool
+	// Fail on moves that can't be fully carried out
+	strict bool
+}
+
+func runCrane(crane Crane, opts RunOptions) (string, error) {
+	stacks, instructions, err := parseInput("./input.txt")
+	if err != nil {
+		return "", err
+	}
+
+	var onMove MoveHandler
+	if opts.renderSteps {
+		fmt.Println(renderStacks(stacks))
+		onMove = func(index int, instruction Instruction, stacks []Stack) {
+			fmt.Println()
+			fmt.Println(index+1, "-", instruction.toString())
+			fmt.Println(renderStacks(stacks))
+		}
+	}
+
+	if err := runInstructions(stacks, instructions, crane, opts.strict, onMove); err != nil {
+		return "", err
+	}
+
+	if opts.render && !opts.renderSteps {
+		fmt.Println(renderStacks(stacks))
+	}
+
+	return topCrates(stacks), nil
+}
+
+func parts(variant QVariant, opts RunOptions) {
+	tops, err := runCrane(partCranes[variant], opts)
+	if err != nil {
+		panic(err)
+	}
+
 	fmt.Println("Part ", variant, " - ", tops)
 }
 
+// Records a frame per instruction and plays or writes out whichever animations were asked for
+func animateCrane(crane Crane, opts RunOptions) {
+	stacks, instructions, err := parseInput("./input.txt")
+	if err != nil {
+		panic(err)
+	}
+
+	// Hold on to the error so the frames up to a bad move still get written
+	frames, runErr := recordFrames(stacks, instructions, crane, opts.strict)
+
+	if *logFile != "" {
+		err := writeFile(*logFile, func(w io.Writer) error {
+			return writeFrameLog(frames, w)
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	if *gifFile != "" {
+		err := writeFile(*gifFile, func(w io.Writer) error {
+			return writeFrameGIF(frames, *cellSize, *frameDelay, w)
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	if *animate {
+		playFrames(frames, *frameDelay, os.Stdout)
+	}
+
+	if runErr != nil {
+		panic(runErr)
+	}
+}
+
+// Searches for the moves that get from the input's stacks to the target tops
+func searchCrane(crane Crane, target string, opts SearchOptions) {
+	stacks, _, err := parseInput("./input.txt")
+	if err != nil {
+		panic(err)
+	}
+
+	instructions, err := searchForTops(stacks, target, crane, opts)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, instruction := range instructions {
+		fmt.Println(instruction.toString())
+	}
+
+	if err := runInstructions(stacks, instructions, crane, true, nil); err != nil {
+		panic(err)
+	}
+	fmt.Println(renderStacks(stacks))
+}
+
+var (
+	craneName     = flag.String("crane", "", "only run this crane model: "+craneNames())
+	craneCapacity = flag.Int("capacity", 3, "most crates the capacity crane can lift at once")
+	render        = flag.Bool("render", false, "draw the stacks once all the moves are done")
+	renderSteps   = flag.Bool("render-steps", false, "draw the stacks after every move")
+	strict        = flag.Bool("strict", false, "fail on moves with too few crates to carry out")
+
+	// Animations use the crane from -crane, or the CrateMover 9000 if it isn't set
+	animate    = flag.Bool("animate", false, "play the moves in the terminal")
+	frameDelay = flag.Duration("delay", 200*time.Millisecond, "time between animation frames")
+	gifFile    = flag.String("gif", "", "write the moves as an animated GIF")
+	logFile    = flag.String("log", "", "write every move as text")
+	cellSize   = flag.Int("cell-size", 12, "size of a crate in the GIF, in pixels")
+
+	// Searching also uses the crane from -crane, or the CrateMover 9000
+	target    = flag.String("target", "", "search for the fewest moves that leave these top crates")
+	algorithm = flag.String("algorithm", string(AStar), "search algorithm, astar or bfs")
+	maxDepth  = flag.Int("max-depth", 6, "most moves the search will try")
+	maxStates = flag.Int("max-states", 200000, "most arrangements the search will queue")
+)
+
+// The crane from -crane, falling back to the CrateMover 9000
+func selectedCrane() Crane {
+	name := *craneName
+	if name == "" {
+		name = "9000"
+	}
+
+	crane, err := newCrane(name, *craneCapacity)
+	if err != nil {
+		panic(err)
+	}
+
+	return crane
+}
+
 func main() {
-	parts(Part1)
-	parts(Part2)
+	flag.Parse()
+	opts := RunOptions{render: *render, renderSteps: *renderSteps, strict: *strict}
+
+	if *target != "" {
+		searchOpts := SearchOptions{
+			algorithm: SearchAlgorithm(*algorithm),
+			maxDepth:  *maxDepth,
+			maxStates: *maxStates,
+		}
+		searchCrane(selectedCrane(), *target, searchOpts)
+		return
+	}
+
+	if *animate || *gifFile != "" || *logFile != "" {
+		animateCrane(selectedCrane(), opts)
+		return
+	}
+
+	if *craneName != "" {
+		crane, err := newCrane(*craneName, *craneCapacity)
+		if err != nil {
+			panic(err)
+		}
+
+		tops, err := runCrane(crane, opts)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println("Crane ", *craneName, " - ", tops)
+		return
+	}
+
+	parts(Part1, opts)
+	parts(Part2, opts)
 }
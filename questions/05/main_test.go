@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var exampleMoves = []string{
+	"move 1 from 2 to 1",
+	"move 3 from 1 to 3",
+	"move 2 from 2 to 1",
+	"move 1 from 1 to 2",
+}
+
+func parseMoves(t *testing.T, moves []string) []Instruction {
+	instructions, err := parseInstructions(moves)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return instructions
+}
+
+func TestExampleTops(t *testing.T) {
+	for crane, want := range map[Crane]string{CrateMover9000{}: "CMZ", CrateMover9001{}: "MCD"} {
+		stacks := exampleStacks(t)
+		err := runInstructions(stacks, parseMoves(t, exampleMoves), crane, true, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := topCrates(stacks); got != want {
+			t.Errorf("%T: got %v, want %v", crane, got, want)
+		}
+	}
+}
+
+func TestStrictMoveError(t *testing.T) {
+	moves := []string{"move 1 from 2 to 1", "move 5 from 2 to 3", "move 1 from 1 to 2"}
+	stacks := exampleStacks(t)
+	err := runInstructions(stacks, parseMoves(t, moves), CrateMover9000{}, true, nil)
+
+	var moveErr *MoveError
+	if !errors.As(err, &moveErr) {
+		t.Fatalf("got %v, want a MoveError", err)
+	}
+
+	if moveErr.index != 1 {
+		t.Errorf("got index %v, want 1", moveErr.index)
+	}
+	if moveErr.instruction != (Instruction{source: 1, destination: 2, total: 5}) {
+		t.Errorf("got instruction %v", moveErr.instruction.toString())
+	}
+	if moveErr.reason != "can't move 5 crates, stack 2 only has 2" {
+		t.Errorf("got reason %q", moveErr.reason)
+	}
+
+	// The stacks as they were when the bad move came up
+	want := []Stack{{"Z", "N", "D"}, {"M", "C"}, {"P"}}
+	if !reflect.DeepEqual(moveErr.stacks, want) {
+		t.Errorf("got stacks %v, want %v", moveErr.stacks, want)
+	}
+	if !strings.HasPrefix(err.Error(), "instruction 2 (move 5 from 2 to 3): can't move 5") ||
+		!strings.HasSuffix(err.Error(), renderStacks(want)) {
+		t.Errorf("got message\n%v", err)
+	}
+
+	// Outside strict mode it moves what's there
+	stacks = exampleStacks(t)
+	if err := runInstructions(stacks, parseMoves(t, moves), CrateMover9000{}, false, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := topCrates(stacks); got != "NDM" {
+		t.Errorf("got tops %v, want NDM", got)
+	}
+}
+
+func TestMoveErrors(t *testing.T) {
+	tests := []struct {
+		move   string
+		strict bool
+		reason string
+	}{
+		{"move 1 from 4 to 1", false, "there's no stack 4"},
+		{"move 1 from 1 to 0", false, "there's no stack 0"},
+		{"move 1 from 2 to 2", true, "source and destination are the same stack"},
+	}
+
+	for _, test := range tests {
+		err := runInstructions(
+			exampleStacks(t),
+			parseMoves(t, []string{test.move}),
+			CrateMover9000{},
+			test.strict,
+			nil,
+		)
+
+		var moveErr *MoveError
+		if !errors.As(err, &moveErr) || moveErr.reason != test.reason || moveErr.index != 0 {
+			t.Errorf("%v: got %v, want %v", test.move, err, test.reason)
+		}
+	}
+
+	// Moving onto the same stack is skipped when not strict
+	stacks := exampleStacks(t)
+	sameStack := parseMoves(t, []string{"move 1 from 2 to 2"})
+	err := runInstructions(stacks, sameStack, CrateMover9000{}, false, nil)
+	if err != nil || !reflect.DeepEqual(stacks, exampleStacks(t)) {
+		t.Errorf("got %v and stacks %v", err, stacks)
+	}
+}
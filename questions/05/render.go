@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Each column is wide enough for its widest crate or its stack number, whichever is wider
+func columnWidths(stacks []Stack) []int {
+	widths := make([]int, len(stacks))
+	for i, stack := range stacks {
+		widths[i] = len(strconv.Itoa(i + 1))
+		for _, crate := range stack {
+			// Crates are wrapped in brackets
+			if len(crate)+2 > widths[i] {
+				widths[i] = len(crate) + 2
+			}
+		}
+
+		// Keep at least the puzzle's 3 width so single digit stacks line up
+		if widths[i] < 3 {
+			widths[i] = 3
+		}
+	}
+
+	return widths
+}
+
+// Centers s in width, leaning left when it can't be exactly centered
+func center(s string, width int) string {
+	left := (width - len(s)) / 2
+	right := width - len(s) - left
+	return strings.Repeat(" ", left) + s + strings.Repeat(" ", right)
+}
+
+// Draws the stacks the same way the puzzle input does. Crates are in brackets with
+// a space between columns, and the stack numbers are along the bottom.
+// Trailing spaces are trimmed from the crate rows but not the legend, like the input
+func renderStacks(stacks []Stack) string {
+	return renderStacksWith(stacks, nil)
+}
+
+// Lets the caller wrap a crate's cell, like with terminal colors, once it's been padded
+type CellDecorator func(stackIndex int, height int, cell string) string
+
+// Same as renderStacks, but every crate's cell goes through decorate if it's set
+func renderStacksWith(stacks []Stack, decorate CellDecorator) string {
+	widths := columnWidths(stacks)
+
+	tallest := 0
+	for _, stack := range stacks {
+		if len(stack) > tallest {
+			tallest = len(stack)
+		}
+	}
+
+	lines := []string{}
+	for height := tallest - 1; height >= 0; height -= 1 {
+		cells := make([]string, len(stacks))
+		for i, stack := range stacks {
+			if height < len(stack) {
+				cells[i] = center("["+stack[height]+"]", widths[i])
+				if decorate != nil {
+					cells[i] = decorate(i, height, cells[i])
+				}
+			} else {
+				cells[i] = strings.Repeat(" ", widths[i])
+			}
+		}
+
+		lines = append(lines, strings.TrimRight(strings.Join(cells, " "), " "))
+	}
+
+	legend := make([]string, len(stacks))
+	for i := range stacks {
+		legend[i] = center(strconv.Itoa(i+1), widths[i])
+	}
+	lines = append(lines, strings.Join(legend, " "))
+
+	return strings.Join(lines, "\n")
+}
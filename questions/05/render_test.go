@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// The drawing from the puzzle's example, trailing spaces and all
+const exampleDrawing = `    [D]
+[N] [C]
+[Z] [M] [P]
+ 1   2   3 `
+
+func TestRenderExampleRoundTrip(t *testing.T) {
+	stacks, err := parseRows(strings.Split(exampleDrawing, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Stack{{"Z", "N"}, {"M", "C", "D"}, {"P"}}
+	if !reflect.DeepEqual(stacks, want) {
+		t.Fatalf("parsed %v, want %v", stacks, want)
+	}
+
+	if got := renderStacks(stacks); got != exampleDrawing {
+		t.Errorf("got\n%q\nwant\n%q", got, exampleDrawing)
+	}
+}
+
+func TestRenderParseRoundTrip(t *testing.T) {
+	many := make([]Stack, 12)
+	for i := range many {
+		for height := 0; height <= i%4; height += 1 {
+			many[i] = append(many[i], fmt.Sprint(string(rune('A'+i)), height))
+		}
+	}
+	// A gap in the middle of the drawing
+	many[5] = Stack{}
+
+	tests := []struct {
+		name   string
+		stacks []Stack
+	}{
+		{"single letters", []Stack{{"A"}, {"B", "C"}, {}}},
+		{"multi char labels", []Stack{{"AB", "C"}, {"LONG"}, {"X", "YY", "ZZZ"}}},
+		{"more than 9 stacks", many},
+		{"empty stacks", []Stack{{}, {}}},
+	}
+
+	for _, test := range tests {
+		drawing := renderStacks(test.stacks)
+		parsed, err := parseRows(strings.Split(drawing, "\n"))
+		if err != nil {
+			t.Errorf("%v: %v\n%v", test.name, err, drawing)
+			continue
+		}
+
+		if !reflect.DeepEqual(parsed, test.stacks) {
+			t.Errorf("%v: parsed %v, want %v\n%v", test.name, parsed, test.stacks, drawing)
+		}
+	}
+}
+
+func TestParseRowsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		drawing []string
+		want    string
+	}{
+		{
+			"crate over no stack number",
+			[]string{"        [A]", "[Z]", " 1   2 "},
+			"line 1, column 9: crate isn't above any stack number",
+		},
+		{
+			"crate spanning two stacks",
+			[]string{"[AAAAA]", " 1   2 "},
+			"line 1, column 1: crate spans stacks 1 and 2",
+		},
+		{
+			"missing ]",
+			[]string{"[M] [Z", " 1   2 "},
+			"line 1, column 5: crate is missing its ]",
+		},
+		{
+			"not a crate",
+			[]string{"[Z] M", " 1   2 "},
+			"line 1, column 5: expected a crate, found 'M'",
+		},
+		{
+			"floating over a gap",
+			[]string{"    [D]", "[N]", "[Z]     [P]", " 1   2   3 "},
+			"line 1, column 5: crate D isn't sitting on top of stack 2",
+		},
+		{
+			"legend out of order",
+			[]string{"[Z] [M]", " 1   3 "},
+			"line 2: legend has stack 3 where stack 2 should be",
+		},
+		{
+			"legend that isn't numbers",
+			[]string{"[Z] [M]", " 1   a "},
+			`line 2: legend has "a", which isn't a stack number`,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := parseRows(test.drawing)
+		if fmt.Sprint(err) != test.want {
+			t.Errorf("%v: got error %v, want %v", test.name, err, test.want)
+		}
+	}
+}
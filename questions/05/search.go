@@ -0,0 +1,210 @@
+package main
+
+import (
+	"container/heap"
+	"fmt"
+	"strings"
+)
+
+type SearchAlgorithm string
+
+const (
+	BreadthFirst SearchAlgorithm = "bfs"
+	AStar        SearchAlgorithm = "astar"
+)
+
+type SearchOptions struct {
+	algorithm SearchAlgorithm
+	// Longest sequence of moves to try
+	maxDepth int
+	// Most arrangements to queue before giving up. Everything queued is kept in memory
+	maxStates int
+}
+
+// An arrangement of stacks, and how we got there
+type searchNode struct {
+	stacks      []Stack
+	parent      *searchNode
+	instruction Instruction
+	depth       int
+	// depth plus the heuristic
+	estimate int
+	// Breaks ties so the search is the same every run
+	order int
+}
+
+// Priority queue of nodes, cheapest estimate first. Among equal estimates the deeper
+// node goes first since it's closer to a solution
+type searchQueue []*searchNode
+
+func (q searchQueue) Len() int { return len(q) }
+
+func (q searchQueue) Less(i, j int) bool {
+	if q[i].estimate != q[j].estimate {
+		return q[i].estimate < q[j].estimate
+	}
+	if q[i].depth != q[j].depth {
+		return q[i].depth > q[j].depth
+	}
+	return q[i].order < q[j].order
+}
+
+func (q searchQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *searchQueue) Push(x any) { *q = append(*q, x.(*searchNode)) }
+
+func (q *searchQueue) Pop() any {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
+
+// Identifies an arrangement, so we don't look at it twice
+func stacksKey(stacks []Stack) string {
+	var sb strings.Builder
+	for _, stack := range stacks {
+		for _, crate := range stack {
+			sb.WriteString(crate)
+			sb.WriteByte(0)
+		}
+		sb.WriteByte(1)
+	}
+
+	return sb.String()
+}
+
+// A lower bound on the moves left. It only works when every stack needs a single
+// character crate on top. A move only changes the tops of two stacks, so it can fix
+// at most two of them. Otherwise it's 0, which makes the search breadth first
+func topsHeuristic(target string) func(stacks []Stack) int {
+	return func(stacks []Stack) int {
+		if len(target) != len(stacks) {
+			return 0
+		}
+
+		wrong := 0
+		for i, stack := range stacks {
+			top, ok := stack.TryPeek()
+			if !ok || top != target[i:i+1] {
+				wrong += 1
+			}
+		}
+
+		return (wrong + 1) / 2
+	}
+}
+
+// Every move the crane can make from here, in a fixed order
+func nextMoves(stacks []Stack) []Instruction {
+	moves := []Instruction{}
+	for source, stack := range stacks {
+		for destination := range stacks {
+			if source == destination {
+				continue
+			}
+
+			for total := 1; total <= len(stack); total += 1 {
+				moves = append(moves, Instruction{
+					source:      source,
+					destination: destination,
+					total:       total,
+				})
+			}
+		}
+	}
+
+	return moves
+}
+
+// Runs a single move on a copy of the stacks. Only the two stacks that change are copied
+func applyMove(stacks []Stack, instruction Instruction, crane Crane) []Stack {
+	next := make([]Stack, len(stacks))
+	copy(next, stacks)
+
+	source := append(Stack{}, stacks[instruction.source]...)
+	destination := append(Stack{}, stacks[instruction.destination]...)
+	crane.move(&source, &destination, instruction.total)
+
+	next[instruction.source] = source
+	next[instruction.destination] = destination
+	return next
+}
+
+func (n *searchNode) instructions() []Instruction {
+	instructions := make([]Instruction, n.depth)
+	for node := n; node.parent != nil; node = node.parent {
+		instructions[node.depth-1] = node.instruction
+	}
+
+	return instructions
+}
+
+// Finds a shortest list of moves that leaves target as the top crates
+func searchForTops(
+	stacks []Stack,
+	target string,
+	crane Crane,
+	opts SearchOptions,
+) ([]Instruction, error) {
+	heuristic := func([]Stack) int { return 0 }
+	switch opts.algorithm {
+	case AStar:
+		heuristic = topsHeuristic(target)
+	case BreadthFirst:
+	default:
+		return nil, fmt.Errorf("unknown search algorithm %q", opts.algorithm)
+	}
+
+	order := 0
+	start := &searchNode{stacks: copyStacks(stacks), estimate: heuristic(stacks)}
+	queue := &searchQueue{start}
+
+	// Arrangements are marked as they're queued rather than when they're looked at, so
+	// the queue can't fill up with repeats. It keeps the shallowest depth each one was
+	// queued at, since A* can find a shorter way to an arrangement after the first
+	queued := map[string]int{stacksKey(start.stacks): 0}
+
+	for queue.Len() > 0 {
+		node := heap.Pop(queue).(*searchNode)
+
+		// A shorter way here was queued after this one
+		if node.depth > queued[stacksKey(node.stacks)] {
+			continue
+		}
+
+		if topCrates(node.stacks) == target {
+			return node.instructions(), nil
+		}
+
+		if node.depth >= opts.maxDepth {
+			continue
+		}
+
+		for _, move := range nextMoves(node.stacks) {
+			next := applyMove(node.stacks, move, crane)
+			key := stacksKey(next)
+			if depth, exists := queued[key]; exists && depth <= node.depth+1 {
+				continue
+			}
+
+			// Everything queued stays in memory, so this is what the limit has to count
+			if order+1 >= opts.maxStates {
+				return nil, fmt.Errorf("gave up after queueing %v arrangements", order+1)
+			}
+
+			order += 1
+			queued[key] = node.depth + 1
+			heap.Push(queue, &searchNode{
+				stacks:      next,
+				parent:      node,
+				instruction: move,
+				depth:       node.depth + 1,
+				estimate:    node.depth + 1 + heuristic(next),
+				order:       order,
+			})
+		}
+	}
+
+	return nil, fmt.Errorf("%q can't be reached in %v moves or fewer", target, opts.maxDepth)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func exampleStacks(t *testing.T) []Stack {
+	stacks, err := parseRows(strings.Split(exampleDrawing, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return stacks
+}
+
+func TestSearchFindsShortestMoves(t *testing.T) {
+	for _, algorithm := range []SearchAlgorithm{BreadthFirst, AStar} {
+		opts := SearchOptions{algorithm: algorithm, maxDepth: 6, maxStates: 100000}
+		instructions, err := searchForTops(exampleStacks(t), "CMZ", CrateMover9000{}, opts)
+		if err != nil {
+			t.Fatalf("%v: %v", algorithm, err)
+		}
+
+		// Moving Z onto P, and then C and D onto the empty stack
+		if len(instructions) != 2 {
+			t.Errorf("%v: took %v moves, want 2", algorithm, len(instructions))
+		}
+
+		stacks := exampleStacks(t)
+		if err := runInstructions(stacks, instructions, CrateMover9000{}, true, nil); err != nil {
+			t.Fatal(err)
+		}
+		if got := topCrates(stacks); got != "CMZ" {
+			t.Errorf("%v: moves leave %v on top, want CMZ", algorithm, got)
+		}
+	}
+}
+
+func TestSearchGivesUpAtTheStateLimit(t *testing.T) {
+	opts := SearchOptions{algorithm: BreadthFirst, maxDepth: 20, maxStates: 50}
+	_, err := searchForTops(exampleStacks(t), "ZZZ", CrateMover9000{}, opts)
+	if err == nil || !strings.Contains(err.Error(), "gave up") {
+		t.Errorf("got %v, want the search to give up", err)
+	}
+}
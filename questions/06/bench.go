@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A stream that keeps cycling through window-1 letters, so no window is unique until
+// the window distinct letters tacked on the end. Every finder has to scan all of it.
+// Letters go past ASCII for windows over 128
+func syntheticStream(size int, window int) string {
+	letter := func(i int) rune {
+		if i < 128-'!' {
+			return '!' + rune(i)
+		}
+		return 0x100 + rune(i)
+	}
+
+	var sb strings.Builder
+	for i := 0; sb.Len() < size; i += 1 {
+		sb.WriteRune(letter(i % (window - 1)))
+	}
+
+	for i := 0; i < window; i += 1 {
+		sb.WriteRune(letter(i))
+	}
+
+	return sb.String()
+}
+
+type markerFinder struct {
+	name string
+	find func(input string, window int) int
+	// The byte finder can't handle runes past ASCII
+	asciiOnly bool
+}
+
+var markerFinders = []markerFinder{
+	{name: "map scan", find: findStarterMarker},
+	{name: "duplicate count", find: findStarterMarkerFast},
+	{
+		name: "bytes",
+		find: func(input string, window int) int {
+			return findStarterMarkerBytes([]byte(input), window)
+		},
+		asciiOnly: true,
+	},
+}
+
+// Runs the finder b.N times. Shared by the -bench mode and go test -bench
+func benchmarkFinder(finder markerFinder, input string, window int) func(b *testing.B) {
+	return func(b *testing.B) {
+		b.SetBytes(int64(len(input)))
+		for i := 0; i < b.N; i += 1 {
+			finder.find(input, window)
+		}
+	}
+}
+
+// Benchmarks every finder on a synthetic stream of about size bytes for each window
+func runBenchmark(size int, windows []int, w io.Writer) error {
+	for _, window := range windows {
+		if window < 2 {
+			return fmt.Errorf("window %v should be at least 2", window)
+		}
+
+		input := syntheticStream(size, window)
+		ascii := isASCII(input)
+		fmt.Fprintf(w, "window %v, %v bytes\n", window, len(input))
+
+		for _, finder := range markerFinders {
+			if finder.asciiOnly && !ascii {
+				fmt.Fprintf(w, "  %-16v skipped, stream isn't ASCII\n", finder.name)
+				continue
+			}
+
+			result := testing.Benchmark(benchmarkFinder(finder, input, window))
+			elapsed := time.Duration(result.NsPerOp())
+
+			fmt.Fprintf(
+				w,
+				"  %-16v %12v  %8.1f MB/s  %5v runs  marker %v\n",
+				finder.name,
+				elapsed.Round(time.Microsecond),
+				float64(len(input))/elapsed.Seconds()/1e6,
+				result.N,
+				finder.find(input, window),
+			)
+		}
+	}
+
+	return nil
+}
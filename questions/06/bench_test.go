@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Same streams as the -bench mode, run with go test -bench .
+func BenchmarkMarkerFinders(b *testing.B) {
+	for _, window := range []int{4, 14, 64, 256} {
+		input := syntheticStream(1<<20, window)
+		ascii := isASCII(input)
+
+		for _, finder := range markerFinders {
+			if finder.asciiOnly && !ascii {
+				continue
+			}
+
+			name := fmt.Sprintf("window=%v/%v", window, finder.name)
+			b.Run(name, benchmarkFinder(finder, input, window))
+		}
+	}
+}
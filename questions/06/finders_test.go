@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestMarkerFindersAgree(t *testing.T) {
+	tests := []struct {
+		input string
+		// Index of the last rune of the first marker for 4 and 14
+		want4  int
+		want14 int
+	}{
+		{"mjqjpqmgbljsphdztnvjfqwrcgsmlb", 6, 18},
+		{"bvwbjplbgvbhsrlpgdmjqwftvncz", 4, 22},
+		{"nppdvjthqldpwncqszvftbrmjlhg", 5, 22},
+		{"nznrnfrfntjfmvfwmzdfjlvtqnbhcprsg", 9, 28},
+		{"zcfzfwzzqfrljwzlrfnpqdbhtmscgvjw", 10, 25},
+		{"abcd", 3, -1},
+		{"abc", -1, -1},
+		{"aaaaaaaa", -1, -1},
+		{"", -1, -1},
+	}
+
+	for _, test := range tests {
+		for window, want := range map[int]int{4: test.want4, 14: test.want14} {
+			for _, finder := range markerFinders {
+				if got := finder.find(test.input, window); got != want {
+					t.Errorf("%v on %q, window %v: got %v, want %v", finder.name, test.input, window, got, want)
+				}
+			}
+		}
+	}
+}
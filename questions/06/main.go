@@ -1,9 +1,14 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"runtime"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 type QVariant int64
@@ -71,20 +76,196 @@ func findStarterMarker(input string, totalUniqueChars int) int {
 	return -1
 }
 
-func parts(variant QVariant) {
-	// There's only one line in this input
-	input := readLines("./input.txt")[0]
+// Same as findStarterMarker, but keeps count of how many letters show up more than once
+// in the window. The window is unique when that's 0, so each step is O(1)
+func findStarterMarkerFast(input string, totalUniqueChars int) int {
+	runes := []rune(input)
+	if len(runes) < totalUniqueChars {
+		return -1
+	}
+
+	occurrences := map[rune]int{}
+	duplicates := 0
+	for end := 0; end < len(runes); end += 1 {
+		occurrences[runes[end]] += 1
+		if occurrences[runes[end]] == 2 {
+			duplicates += 1
+		}
+
+		// Drop the rune that just fell out of the window
+		start := end - totalUniqueChars
+		if start >= 0 {
+			if occurrences[runes[start]] == 2 {
+				duplicates -= 1
+			}
+			occurrences[runes[start]] -= 1
+		}
 
-	totalUniqueChars := 4
-	if variant == Part2 {
-		totalUniqueChars = 14
+		if end >= totalUniqueChars-1 && duplicates == 0 {
+			return end
+		}
 	}
 
-	// Output the index for the question as if it was in a list where the first index is 1
-	fmt.Println("Part ", variant, " - ", findStarterMarker(input, totalUniqueChars)+1)
+	return -1
 }
 
+// findStarterMarkerFast for ASCII streams, which can skip the map and the rune conversion
+func findStarterMarkerBytes(input []byte, totalUniqueChars int) int {
+	if len(input) < totalUniqueChars {
+		return -1
+	}
+
+	occurrences := [256]int{}
+	duplicates := 0
+	for end := 0; end < len(input); end += 1 {
+		occurrences[input[end]] += 1
+		if occurrences[input[end]] == 2 {
+			duplicates += 1
+		}
+
+		start := end - totalUniqueChars
+		if start >= 0 {
+			if occurrences[input[start]] == 2 {
+				duplicates -= 1
+			}
+			occurrences[input[start]] -= 1
+		}
+
+		if end >= totalUniqueChars-1 && duplicates == 0 {
+			return end
+		}
+	}
+
+	return -1
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i += 1 {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Window size for each part of the puzzle
+var partWindows = map[QVariant]int{
+	Part1: 4,
+	Part2: 14,
+}
+
+// Opens the input, with "-" being stdin
+func openInput(filename string) (io.ReadCloser, error) {
+	if filename == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	return os.Open(filename)
+}
+
+// Reads the input once, and prints the first marker for each window, or every marker
+// if all is set. Every line is its own datastream. Markers are output as if the stream
+// was a list where the first index is 1
+func reportMarkers(filename string, windows []int, labels []string, all bool) {
+	input, err := openInput(filename)
+	if err != nil {
+		panic(err)
+	}
+	defer input.Close()
+
+	results, err := scanStreams(input, windows, all, runtime.NumCPU())
+	if err != nil {
+		panic(err)
+	}
+
+	for _, result := range results {
+		// Only bother saying which line it is if there's more than one
+		prefix := ""
+		if len(results) > 1 {
+			prefix = fmt.Sprint("Line ", result.line, " ")
+		}
+
+		for i, window := range windows {
+			found := []string{}
+			for _, index := range result.markers[window] {
+				found = append(found, strconv.Itoa(index+1))
+			}
+
+			if len(found) == 0 {
+				found = append(found, "none")
+			}
+
+			fmt.Println(prefix+labels[i], " - ", strings.Join(found, " "))
+		}
+	}
+}
+
+func parts(filename string, all bool) {
+	windows := []int{partWindows[Part1], partWindows[Part2]}
+	// Spaced the same as the original Println("Part ", variant, " - ", marker)
+	reportMarkers(filename, windows, []string{"Part  1", "Part  2"}, all)
+}
+
+// Parses a comma separated list like "4,14"
+func parseIntList(s string) ([]int, error) {
+	values := []int{}
+	for _, token := range strings.Split(s, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(token))
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+var (
+	inputFile    = flag.String("input", "./input.txt", "datastream to read, or - for stdin")
+	windowList   = flag.String("windows", "", "window sizes to use instead of 4 and 14, like 4,14")
+	allMarkers   = flag.Bool("all", false, "report every marker, not just the first")
+	bench        = flag.Bool("bench", false, "time each marker finder on synthetic streams and exit")
+	benchSize    = flag.Int("bench-size", 4<<20, "size of each synthetic stream, in bytes")
+	benchWindows = flag.String("bench-windows", "4,14,64,256", "window sizes to benchmark")
+)
+
 func main() {
-	parts(Part1)
-	parts(Part2)
+	flag.Parse()
+
+	if *bench {
+		windows, err := parseIntList(*benchWindows)
+		if err != nil {
+			panic(err)
+		}
+
+		if err := runBenchmark(*benchSize, windows, os.Stdout); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	if *windowList != "" {
+		windows, err := parseIntList(*windowList)
+		if err != nil {
+			panic(err)
+		}
+
+		// Each window only needs looking for once
+		seen := map[int]bool{}
+		unique := []int{}
+		labels := []string{}
+		for _, window := range windows {
+			if !seen[window] {
+				seen[window] = true
+				unique = append(unique, window)
+				labels = append(labels, fmt.Sprint("Window ", window))
+			}
+		}
+
+		reportMarkers(*inputFile, unique, labels, *allMarkers)
+		return
+	}
+
+	parts(*inputFile, *allMarkers)
 }
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExampleStreams(t *testing.T) {
+	file, err := os.Open("./example.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	results, err := scanStreams(file, []int{4, 14}, false, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// From the puzzle, counting from 1
+	want := map[int][]int{
+		4:  {7, 5, 6, 10, 11},
+		14: {19, 23, 23, 29, 26},
+	}
+
+	for window, markers := range want {
+		got := []int{}
+		for _, result := range results {
+			for _, index := range result.markers[window] {
+				got = append(got, index+1)
+			}
+		}
+
+		if !reflect.DeepEqual(got, markers) {
+			t.Errorf("window %v: got %v, want %v", window, got, markers)
+		}
+	}
+}
+
+// Hands out a byte at a time, so lines get split across reads
+type slowReader struct {
+	r io.Reader
+}
+
+func (s slowReader) Read(p []byte) (int, error) {
+	return s.r.Read(p[:1])
+}
+
+func TestScanStreamsLineEndings(t *testing.T) {
+	input := "mjqjpqmgbljsphdztnvjfqwrcgsmlb\r\n\r\n\nbvwbjplbgvbhsrlpgdmjqwftvncz"
+	results, err := scanStreams(slowReader{strings.NewReader(input)}, []int{4}, false, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("got %v streams, want 2", len(results))
+	}
+
+	if results[0].line != 1 || results[1].line != 4 {
+		t.Errorf("got lines %v and %v, want 1 and 4", results[0].line, results[1].line)
+	}
+
+	if results[0].markers[4][0] != 6 || results[1].markers[4][0] != 4 {
+		t.Errorf("got markers %v and %v", results[0].markers, results[1].markers)
+	}
+}
+
+func TestScanStreamsAll(t *testing.T) {
+	// Every run of 3 is different until the aa near the end
+	input := "abcabcaab\nxyzx"
+
+	tests := []struct {
+		all  bool
+		want []map[int][]int
+	}{
+		{true, []map[int][]int{{3: {2, 3, 4, 5, 6}}, {3: {2, 3}, 4: nil}}},
+		{false, []map[int][]int{{3: {2}}, {3: {2}}}},
+	}
+
+	for _, test := range tests {
+		results, err := scanStreams(strings.NewReader(input), []int{3, 4}, test.all, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(results) != 2 {
+			t.Fatalf("got %v streams, want 2", len(results))
+		}
+
+		for i, result := range results {
+			for window, want := range test.want[i] {
+				if got := result.markers[window]; !reflect.DeepEqual(got, want) {
+					t.Errorf("all %v, line %v, window %v: got %v, want %v", test.all, i+1, window, got, want)
+				}
+			}
+
+			// abcabcaab never has 4 different letters in a row
+			if i == 0 && len(result.markers[4]) != 0 {
+				t.Errorf("all %v: line 1 found window 4 markers %v", test.all, result.markers[4])
+			}
+		}
+	}
+}
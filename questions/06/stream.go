@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Watches a stream for windows of distinct runes. Only the last window runes are kept,
+// in a ring buffer, so the stream can be any length
+type MarkerDetector struct {
+	window      int
+	ring        []rune
+	occurrences map[rune]int
+	duplicates  int
+	// How many runes have been pushed
+	total int
+	// Set once the caller doesn't want any more markers
+	done bool
+}
+
+func NewMarkerDetector(window int) (*MarkerDetector, error) {
+	if window < 1 {
+		return nil, fmt.Errorf("window %v should be at least 1", window)
+	}
+
+	return &MarkerDetector{
+		window:      window,
+		ring:        make([]rune, window),
+		occurrences: map[rune]int{},
+	}, nil
+}
+
+// Adds the next rune. Returns true if the last window runes are all different
+func (d *MarkerDetector) push(c rune) bool {
+	// Drop the rune that's falling out of the window to make room
+	slot := d.total % d.window
+	if d.total >= d.window {
+		old := d.ring[slot]
+		if d.occurrences[old] == 2 {
+			d.duplicates -= 1
+		}
+
+		// Delete it if it's 0 so the map never holds more than the window
+		if d.occurrences[old] == 1 {
+			delete(d.occurrences, old)
+		} else {
+			d.occurrences[old] -= 1
+		}
+	}
+
+	d.ring[slot] = c
+	d.occurrences[c] += 1
+	if d.occurrences[c] == 2 {
+		d.duplicates += 1
+	}
+	d.total += 1
+
+	return d.total >= d.window && d.duplicates == 0
+}
+
+// Reads the datastream a rune at a time, looking for every window size at once. Calls
+// onMarker with the window and the index of the last rune of every marker. Once onMarker
+// returns false that window stops reporting, and once every window has stopped so does
+// the read. The datastream ends at a line ending or the end of the reader
+func scanMarkers(r io.Reader, windows []int, onMarker func(window int, index int) bool) error {
+	detectors := []*MarkerDetector{}
+	for _, window := range windows {
+		detector, err := NewMarkerDetector(window)
+		if err != nil {
+			return err
+		}
+		detectors = append(detectors, detector)
+	}
+
+	reader := bufio.NewReader(r)
+	remaining := len(detectors)
+	for index := 0; remaining > 0; index += 1 {
+		c, _, err := reader.ReadRune()
+		if err == io.EOF || (err == nil && (c == '\n' || c == '\r')) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		for _, detector := range detectors {
+			if detector.done || !detector.push(c) {
+				continue
+			}
+
+			if !onMarker(detector.window, index) {
+				detector.done = true
+				remaining -= 1
+			}
+		}
+	}
+
+	return nil
+}
+
+// The markers found in one line of the input
+type StreamResult struct {
+	// 1 indexed, to match an editor
+	line int
+	// Indexes of the markers found for each window
+	markers map[int][]int
+	err     error
+}
+
+// Hands one line's bytes to its scanner as they're read, so the line is never held
+// in memory all at once
+type chunkReader struct {
+	chunks  <-chan []byte
+	current []byte
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	for len(c.current) == 0 {
+		chunk, ok := <-c.chunks
+		if !ok {
+			return 0, io.EOF
+		}
+		c.current = chunk
+	}
+
+	n := copy(p, c.current)
+	c.current = c.current[n:]
+	return n, nil
+}
+
+// How much is read at a time, and how many reads each line can have waiting to be
+// scanned. Together with the workers they bound the memory used however long lines are
+const (
+	chunkSize     = 32 << 10
+	pendingChunks = 4
+)
+
+// Treats every line of the input as its own datastream, and scans them all at the same
+// time with up to workers goroutines. Lines are split up as they're read, and blank
+// lines are skipped. Results are in line order
+func scanStreams(r io.Reader, windows []int, all bool, workers int) ([]StreamResult, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("workers %v should be at least 1", workers)
+	}
+
+	results := []*StreamResult{}
+	semaphore := make(chan bool, workers)
+	var wg sync.WaitGroup
+
+	// Where the line being read goes, or nil between lines
+	var current chan []byte
+	startLine := func(line int) {
+		result := &StreamResult{line: line, markers: map[int][]int{}}
+		results = append(results, result)
+
+		chunks := make(chan []byte, pendingChunks)
+		current = chunks
+
+		wg.Add(1)
+		semaphore <- true
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			result.err = scanMarkers(
+				&chunkReader{chunks: chunks},
+				windows,
+				func(window int, index int) bool {
+					result.markers[window] = append(result.markers[window], index)
+					return all
+				},
+			)
+
+			// The scan can stop before the end of the line, but the reader still
+			// sends the rest of it
+			for range chunks {
+			}
+		}()
+	}
+
+	endLine := func() {
+		if current != nil {
+			close(current)
+			current = nil
+		}
+	}
+
+	buf := make([]byte, chunkSize)
+	line := 1
+	for {
+		n, err := r.Read(buf)
+		data := buf[:n]
+
+		for len(data) > 0 {
+			if current == nil {
+				// Skip to the start of the next datastream
+				skip := 0
+				for skip < len(data) && (data[skip] == '\n' || data[skip] == '\r') {
+					if data[skip] == '\n' {
+						line += 1
+					}
+					skip += 1
+				}
+
+				data = data[skip:]
+				if len(data) == 0 {
+					break
+				}
+				startLine(line)
+			}
+
+			end := bytes.IndexByte(data, '\n')
+			if end < 0 {
+				end = len(data)
+			}
+
+			// Copied since buf is reused for the next read
+			current <- append([]byte{}, data[:end]...)
+			if end < len(data) {
+				endLine()
+				line += 1
+				end += 1
+			}
+			data = data[end:]
+		}
+
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			endLine()
+			wg.Wait()
+			return nil, err
+		}
+	}
+
+	endLine()
+	wg.Wait()
+
+	ordered := []StreamResult{}
+	for _, result := range results {
+		if result.err != nil {
+			return nil, fmt.Errorf("line %v: %w", result.line, result.err)
+		}
+		ordered = append(ordered, *result)
+	}
+
+	return ordered, nil
+}
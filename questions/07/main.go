@@ -6,6 +6,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"questions/01/main/pkg/collections"
 )
 
 type QVariant int64
@@ -98,7 +100,7 @@ func handleCommand(line string, currentNode *Node, root *Node) *Node {
 
 	command, exists := stringToCommand[tokens[1]]
 	if !exists {
-		panic(fmt.Sprintf("token is not a command: ", tokens[1]))
+		panic(fmt.Sprintf("token is not a command: %v", tokens[1]))
 	}
 
 	if command == CD {
@@ -166,19 +168,17 @@ func parseInput(input []string) *Node {
 }
 
 func listDirs(root *Node) []*Node {
-	nodesToVisit := []*Node{
-		root,
-	}
+	nodesToVisit := collections.Queue[*Node]{}
+	nodesToVisit.Push(root)
 
 	directories := []*Node{root}
 
-	for len(nodesToVisit) > 0 {
-		currentNode := nodesToVisit[0]
-		nodesToVisit = nodesToVisit[1:]
+	for !nodesToVisit.IsEmpty() {
+		currentNode := nodesToVisit.Pop()
 		for _, node := range currentNode.children {
 			if node.isDir {
 				directories = append(directories, node)
-				nodesToVisit = append(nodesToVisit, node)
+				nodesToVisit.Push(node)
 			}
 		}
 	}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
-	"time"
+
+	"questions/01/main/pkg/logger"
 )
 
 var (
@@ -62,20 +65,19 @@ type Instruction struct {
 }
 
 var inputToDirMap = map[string]Pair{
-	"L": Left,
-	"R": Right,
-	"U": Up,
-	"D": Down,
+	"L":  Left,
+	"R":  Right,
+	"U":  Up,
+	"D":  Down,
+	"UL": UpLeft,
+	"UR": UpRight,
+	"DL": DownLeft,
+	"DR": DownRight,
 }
 
-func parseToIntOrPanic(s string) int {
-	i, err := strconv.Atoi(s)
-	if err != nil {
-		panic(err)
-	}
-
-	return i
-}
+// A direction followed by a distance. The space between them is optional, so this
+// matches both "R 4" and the compact "R4U4UL3" format
+var instructionRegex, _ = regexp.Compile(`^([A-Za-z]+)\s*([0-9]+)`)
 
 func readLines(inputFile string) []string {
 	dat, err := os.ReadFile(inputFile)
@@ -86,17 +88,69 @@ func readLines(inputFile string) []string {
 	return strings.Split(string(dat), "\n")
 }
 
-func parseInstructions(input []string) []Instruction {
+// Back into the compact format, like "R4 UL3"
+func formatInstructions(instructions []Instruction) string {
+	tokens := []string{}
+	for _, instruction := range instructions {
+		name := "?"
+		for k, v := range inputToDirMap {
+			if v.equals(instruction.direction) {
+				name = k
+			}
+		}
+		tokens = append(tokens, fmt.Sprintf("%v%v", name, instruction.distance))
+	}
+
+	return strings.Join(tokens, " ")
+}
+
+// Parses a single line, which may hold multiple run-length instructions
+func parseLine(line string) ([]Instruction, error) {
 	instructions := []Instruction{}
-	for _, line := range input {
-		tokens := strings.Split(line, " ")
+
+	rest := strings.TrimSpace(line)
+	for len(rest) > 0 {
+		// This returns the original string as match 0
+		matches := instructionRegex.FindStringSubmatch(rest)
+		if matches == nil {
+			return nil, fmt.Errorf("could not parse instruction at %q", rest)
+		}
+
+		direction, exists := inputToDirMap[matches[1]]
+		if !exists {
+			return nil, fmt.Errorf("unknown direction %q", matches[1])
+		}
+
+		distance, err := strconv.Atoi(matches[2])
+		if err != nil {
+			return nil, fmt.Errorf("distance %q: %w", matches[2], err)
+		}
+
 		instructions = append(instructions, Instruction{
-			direction: inputToDirMap[tokens[0]],
-			distance:  parseToIntOrPanic(tokens[1]),
+			direction: direction,
+			distance:  distance,
 		})
+
+		rest = strings.TrimSpace(rest[len(matches[0]):])
 	}
 
-	return instructions
+	return instructions, nil
+}
+
+func parseInstructions(input []string) ([]Instruction, error) {
+	instructions := []Instruction{}
+	for lineNum, line := range input {
+		lineInstructions, err := parseLine(line)
+		if err != nil {
+			// Line numbers are 1 indexed to match an editor
+			return nil, fmt.Errorf("line %v: %w", lineNum+1, err)
+		}
+
+		logger.Verbosef("line %v: %v", lineNum+1, formatInstructions(lineInstructions))
+		instructions = append(instructions, lineInstructions...)
+	}
+
+	return instructions, nil
 }
 
 // If pair1 is adjacent to pair2, including diagonal
@@ -115,45 +169,170 @@ func isAdjecent(pair1 Pair, pair2 Pair) bool {
 	return false
 }
 
-func runInstructions(instructions []Instruction, totalTails int) *map[Pair]bool {
-	knots := make([]*Pair, totalTails+1)
+// Snapshot of the rope after the head has moved a single step
+type Step struct {
+	// Total steps taken so far. 0 is the starting position
+	step int
+	// Index of the instruction that produced this step, or -1 for the starting position
+	instruction int
+	knots       []Pair
+}
+
+func (s Step) toString() string {
+	knots := []string{}
+	for _, knot := range s.knots {
+		knots = append(knots, fmt.Sprintf("%v,%v", knot.x, knot.y))
+	}
+
+	return fmt.Sprintf(
+		"step %v (instruction %v): %v",
+		s.step,
+		s.instruction+1,
+		strings.Join(knots, " "),
+	)
+}
+
+func (s Step) head() Pair {
+	return s.knots[0]
+}
+
+func (s Step) tail() Pair {
+	return s.knots[len(s.knots)-1]
+}
+
+// Steps through the instructions one move of the head at a time
+type Rope struct {
+	instructions []Instruction
+	knots        []Pair
+	step         int
+	// Which instruction we're on, and how far into it we are
+	instrIndex    int
+	instrProgress int
+}
+
+func NewRope(instructions []Instruction, totalTails int) *Rope {
+	return &Rope{
+		instructions: instructions,
+		knots:        make([]Pair, totalTails+1),
+	}
+}
 
-	for i := range knots {
-		knots[i] = &Pair{x: 0, y: 0}
+// Back to the starting position, with all knots at 0,0
+func (r *Rope) reset() {
+	for i := range r.knots {
+		r.knots[i] = Pair{x: 0, y: 0}
 	}
 
-	tailVisited := &map[Pair]bool{
-		*knots[0]: true,
+	r.step = 0
+	r.instrIndex = 0
+	r.instrProgress = 0
+}
+
+func (r *Rope) done() bool {
+	return r.instrIndex >= len(r.instructions)
+}
+
+// Returns a copy of the current state, so it's safe to hold on to
+func (r *Rope) state() Step {
+	knots := make([]Pair, len(r.knots))
+	copy(knots, r.knots)
+
+	instruction := r.instrIndex
+	if r.step == 0 {
+		instruction = -1
+	} else if r.instrProgress == 0 {
+		// We've just finished the previous instruction
+		instruction -= 1
 	}
 
-	for _, instr := range instructions {
-		for i := 0; i < instr.distance; i += 1 {
-			// printCurrentKnots(knots)
-			for knotNum, knot := range knots {
-				if knotNum == 0 {
-					// head can move anywhere
-					knot.add(instr.direction)
-					continue
-				}
+	return Step{step: r.step, instruction: instruction, knots: knots}
+}
 
-				prevKnot := knots[knotNum-1]
-				if !isAdjecent(*prevKnot, *knot) {
-					// move in the direction of the knot in front
-					dir := Pair{x: prevKnot.x - knot.x, y: prevKnot.y - knot.y}
+// Moves the head a single step, and drags the rest of the knots along.
+// Returns false if there are no more instructions
+func (r *Rope) next() bool {
+	// Skip over any instructions that don't move anything
+	for !r.done() && r.instructions[r.instrIndex].distance <= r.instrProgress {
+		r.instrIndex += 1
+		r.instrProgress = 0
+	}
 
-					// Need to normalize though to a unit vector
-					dir.normalize()
-					knot.add(dir)
+	if r.done() {
+		return false
+	}
 
-					// Last knot is the tail
-					if knotNum == totalTails {
-						(*tailVisited)[*knot] = true
-					}
-				}
-			}
+	instr := r.instructions[r.instrIndex]
+	for knotNum := range r.knots {
+		knot := &r.knots[knotNum]
+		if knotNum == 0 {
+			// head can move anywhere
+			knot.add(instr.direction)
+			continue
+		}
+
+		prevKnot := r.knots[knotNum-1]
+		if !isAdjecent(prevKnot, *knot) {
+			// move in the direction of the knot in front
+			dir := Pair{x: prevKnot.x - knot.x, y: prevKnot.y - knot.y}
+
+			// Need to normalize though to a unit vector
+			dir.normalize()
+			knot.add(dir)
+		}
+	}
+
+	r.step += 1
+	r.instrProgress += 1
+	if r.instrProgress >= instr.distance {
+		r.instrIndex += 1
+		r.instrProgress = 0
+	}
+
+	return true
+}
+
+// Moves to the state after step n, rewinding if needed.
+// Returns false if the instructions run out before reaching step n
+func (r *Rope) seek(n int) bool {
+	if n < r.step {
+		r.reset()
+	}
+
+	for r.step < n {
+		if !r.next() {
+			return false
 		}
 	}
 
+	return true
+}
+
+// Calls onStep with the starting position, and then after every step.
+// Stops early if onStep returns false
+func (r *Rope) replay(onStep func(Step) bool) {
+	r.reset()
+	if !onStep(r.state()) {
+		return
+	}
+
+	for r.next() {
+		if !onStep(r.state()) {
+			return
+		}
+	}
+}
+
+func runInstructions(instructions []Instruction, totalTails int) *map[Pair]bool {
+	tailVisited := &map[Pair]bool{}
+
+	NewRope(instructions, totalTails).replay(func(step Step) bool {
+		if logger.Enabled(logger.Trace) {
+			logger.Tracef("%v", step.toString())
+		}
+		(*tailVisited)[step.tail()] = true
+		return true
+	})
+
 	return tailVisited
 }
 
@@ -168,38 +347,44 @@ func countVisited(visited *map[Pair]bool) int {
 	return count
 }
 
-func printCurrentKnots(knots []*Pair) {
-	// Set reasonable max mins
-	minX := -15
-	minY := -15
-	maxX := 15
-	maxY := 15
-
-	knotMap := map[Pair]int{}
-	for i, knot := range knots {
-		knotMap[*knot] = i
+// Draws the knots the same way the puzzle does. H is the head, then each knot by number
+// (or T if there's only one), and s for the start. Earlier knots are drawn over later ones.
+func renderKnots(knots []Pair, topLeft Pair, bottomRight Pair) string {
+	knotMap := map[Pair]string{}
+	for i := len(knots) - 1; i >= 0; i -= 1 {
+		label := strconv.Itoa(i)
+		if i == 0 {
+			label = "H"
+		} else if len(knots) == 2 {
+			label = "T"
+		}
+		knotMap[knots[i]] = label
 	}
 
-	for y := minY; y < maxY; y += 1 {
-		for x := minX; x < maxX; x += 1 {
-			value, exists := knotMap[Pair{x: x, y: y}]
-			if exists {
-				fmt.Print(value)
+	var sb strings.Builder
+	for y := topLeft.y; y <= bottomRight.y; y += 1 {
+		for x := topLeft.x; x <= bottomRight.x; x += 1 {
+			pos := Pair{x: x, y: y}
+			if value, exists := knotMap[pos]; exists {
+				sb.WriteString(value)
+			} else if pos.equals(Pair{x: 0, y: 0}) {
+				sb.WriteString("s")
 			} else {
-				fmt.Print(".")
+				sb.WriteString(".")
 			}
 		}
-		fmt.Println()
+		sb.WriteString("\n")
 	}
 
-	fmt.Println("------")
-
-	time.Sleep(1 * time.Second / 32)
+	return sb.String()
 }
 
 func parts() {
 	input := readLines("./input.txt")
-	instructions := parseInstructions(input)
+	instructions, err := parseInstructions(input)
+	if err != nil {
+		panic(err)
+	}
 
 	visited := runInstructions(instructions, 1)
 	count := countVisited(visited)
@@ -212,5 +397,8 @@ func parts() {
 }
 
 func main() {
+	logger.RegisterFlags()
+	flag.Parse()
+
 	parts()
 }
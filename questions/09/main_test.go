@@ -0,0 +1,218 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var example = []string{
+	"R 4",
+	"U 4",
+	"L 3",
+	"D 1",
+	"R 4",
+	"D 1",
+	"L 5",
+	"R 2",
+}
+
+// Frames from the puzzle's worked example, keyed by step
+var exampleFrames = map[int]string{
+	0: `
+......
+......
+......
+......
+H.....`,
+	1: `
+......
+......
+......
+......
+TH....`,
+	4: `
+......
+......
+......
+......
+s..TH.`,
+	5: `
+......
+......
+......
+....H.
+s..T..`,
+	8: `
+....H.
+....T.
+......
+......
+s.....`,
+	11: `
+.HT...
+......
+......
+......
+s.....`,
+	12: `
+..T...
+.H....
+......
+......
+s.....`,
+	16: `
+......
+....TH
+......
+......
+s.....`,
+	17: `
+......
+....T.
+.....H
+......
+s.....`,
+	22: `
+......
+......
+HT....
+......
+s.....`,
+	24: `
+......
+......
+.TH...
+......
+s.....`,
+}
+
+func TestSeekMatchesExampleFrames(t *testing.T) {
+	instructions, err := parseInstructions(example)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rope := NewRope(instructions, 1)
+	// Go forwards and then backwards, so seek has to rewind
+	for _, step := range []int{0, 1, 4, 5, 8, 11, 12, 16, 17, 22, 24, 12, 4, 0} {
+		if !rope.seek(step) {
+			t.Fatalf("seek(%v) ran out of instructions", step)
+		}
+
+		got := renderKnots(rope.state().knots, Pair{x: 0, y: -4}, Pair{x: 5, y: 0})
+		want := strings.TrimPrefix(exampleFrames[step], "\n") + "\n"
+		if got != want {
+			t.Errorf("step %v:\ngot\n%v\nwant\n%v", step, got, want)
+		}
+	}
+
+	if rope.seek(25) {
+		t.Error("seek(25) should run out of instructions")
+	}
+}
+
+func TestReplayVisitsEveryStep(t *testing.T) {
+	instructions, err := parseInstructions(example)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := 0
+	NewRope(instructions, 1).replay(func(step Step) bool {
+		if step.step != steps {
+			t.Errorf("got step %v, want %v", step.step, steps)
+		}
+		steps += 1
+		return true
+	})
+
+	// The starting position and then one per move
+	if steps != 25 {
+		t.Errorf("replayed %v steps, want 25", steps)
+	}
+}
+
+func TestExampleCounts(t *testing.T) {
+	larger := []string{"R 5", "U 8", "L 8", "D 3", "R 17", "D 10", "L 25", "U 20"}
+
+	tests := []struct {
+		input      []string
+		totalTails int
+		want       int
+	}{
+		{example, 1, 13},
+		{example, 9, 1},
+		{larger, 9, 36},
+	}
+
+	for _, test := range tests {
+		instructions, err := parseInstructions(test.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := countVisited(runInstructions(instructions, test.totalTails))
+		if got != test.want {
+			t.Errorf("%v tails: got %v, want %v", test.totalTails, got, test.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	inputs := []string{
+		"R 99999999999999999999",
+		"X 4",
+		"R",
+	}
+
+	for _, input := range inputs {
+		_, err := parseInstructions([]string{"U 1", input})
+		if err == nil || !strings.HasPrefix(err.Error(), "line 2: ") {
+			t.Errorf("%q: got error %v, want a line 2 error", input, err)
+		}
+	}
+}
+
+func TestParseDiagonalAndRunLength(t *testing.T) {
+	tests := []struct {
+		input []string
+		want  []Instruction
+	}{
+		{
+			[]string{"UL 2", "DR 3"},
+			[]Instruction{{UpLeft, 2}, {DownRight, 3}},
+		},
+		{
+			[]string{"R4U4UL3"},
+			[]Instruction{{Right, 4}, {Up, 4}, {UpLeft, 3}},
+		},
+		{
+			[]string{"R4 UL3", "DL 1"},
+			[]Instruction{{Right, 4}, {UpLeft, 3}, {DownLeft, 1}},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := parseInstructions(test.input)
+		if err != nil {
+			t.Errorf("%q: %v", test.input, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.input, got, test.want)
+		}
+
+		// Formatting and parsing again gives the same instructions
+		formatted := formatInstructions(got)
+		again, err := parseInstructions([]string{formatted})
+		if err != nil {
+			t.Errorf("%q: formatted as %q, which didn't parse: %v", test.input, formatted, err)
+			continue
+		}
+		if !reflect.DeepEqual(again, got) {
+			t.Errorf("%q: formatted as %q, which parsed to %v", test.input, formatted, again)
+		}
+	}
+}
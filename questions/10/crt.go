@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+)
+
+const (
+	crtWidth  = 40
+	crtHeight = 6
+)
+
+// The pixels the CRT has drawn, row by row
+type Framebuffer struct {
+	width  int
+	height int
+	pixels []bool
+}
+
+func NewFramebuffer(width int, height int) *Framebuffer {
+	return &Framebuffer{
+		width:  width,
+		height: height,
+		pixels: make([]bool, width*height),
+	}
+}
+
+func (f *Framebuffer) get(x int, y int) bool {
+	return f.pixels[y*f.width+x]
+}
+
+func (f *Framebuffer) set(x int, y int, lit bool) {
+	f.pixels[y*f.width+x] = lit
+}
+
+// The beam moves left to right, top to bottom, one pixel a cycle, and wraps back to
+// the top when it reaches the end
+func (f *Framebuffer) beamPosition(cycle int) (int, int) {
+	// Cycles are 1 indexed, but the pixels are 0 indexed
+	pixel := (cycle - 1) % len(f.pixels)
+	return pixel % f.width, pixel / f.width
+}
+
+// The sprite is 3 pixels wide, centered on spritePos
+func spriteCovers(x int, spritePos int) bool {
+	return x-1 <= spritePos && x+1 >= spritePos
+}
+
+// Draws the pixel the beam is on for the given cycle
+func (f *Framebuffer) drawCycle(cycle int, spritePos int) {
+	x, y := f.beamPosition(cycle)
+	f.set(x, y, spriteCovers(x, spritePos))
+}
+
+func (f *Framebuffer) toString() string {
+	var sb strings.Builder
+	for y := 0; y < f.height; y += 1 {
+		for x := 0; x < f.width; x += 1 {
+			if f.get(x, y) {
+				sb.WriteString("#")
+			} else {
+				sb.WriteString(".")
+			}
+		}
+
+		if y < f.height-1 {
+			sb.WriteString("\n")
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"questions/01/main/pkg/cpu"
+)
+
+// Stops on a cycle number, or when a register changes to a value
+type Breakpoint struct {
+	// Empty for a cycle breakpoint
+	register string
+	value    int
+}
+
+// Parses "cycle=20" or a register like "X=5"
+func parseBreakpoint(s string) (Breakpoint, error) {
+	name, valueStr, found := strings.Cut(s, "=")
+	if !found || name == "" {
+		return Breakpoint{}, fmt.Errorf("breakpoint %q should look like cycle=N or X=N", s)
+	}
+
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		return Breakpoint{}, fmt.Errorf("breakpoint %q: %w", s, err)
+	}
+
+	if name == "cycle" {
+		return Breakpoint{value: value}, nil
+	}
+
+	return Breakpoint{register: name, value: value}, nil
+}
+
+func (b Breakpoint) hit(record TraceRecord) bool {
+	if b.register == "" {
+		return record.Cycle == b.value
+	}
+
+	return record.Before[b.register] != b.value && record.After[b.register] == b.value
+}
+
+func (b Breakpoint) toString() string {
+	if b.register == "" {
+		return fmt.Sprintf("cycle=%v", b.value)
+	}
+
+	return fmt.Sprintf("%v=%v", b.register, b.value)
+}
+
+// So breakpoints can be given more than once on the command line
+type BreakpointFlags []Breakpoint
+
+func (b *BreakpointFlags) String() string {
+	values := []string{}
+	for _, breakpoint := range *b {
+		values = append(values, breakpoint.toString())
+	}
+
+	return strings.Join(values, ",")
+}
+
+func (b *BreakpointFlags) Set(s string) error {
+	breakpoint, err := parseBreakpoint(s)
+	if err != nil {
+		return err
+	}
+
+	*b = append(*b, breakpoint)
+	return nil
+}
+
+const debuggerHelp = `commands:
+  s [n]        step n cycles (default 1). An empty line also steps
+  c            continue until a breakpoint or the end of the program
+  b <cycle=N>  break after cycle N
+  b <X=N>      break after a cycle where register X changes to N
+  d <index>    delete a breakpoint
+  l            list breakpoints
+  p            print the registers and the screen so far
+  q            quit
+  h            show this help`
+
+// Steps through the program a cycle at a time, reading commands from in
+type Debugger struct {
+	device      *cpu.CPU
+	screen      *Framebuffer
+	breakpoints []Breakpoint
+	out         io.Writer
+	// Set by the trace hook at the end of each cycle
+	last TraceRecord
+}
+
+func NewDebugger(instructions []cpu.Instruction, breakpoints []Breakpoint, out io.Writer) (
+	*Debugger,
+	error,
+) {
+	d := &Debugger{
+		device:      newDevice(),
+		screen:      NewFramebuffer(crtWidth, crtHeight),
+		breakpoints: breakpoints,
+		out:         out,
+	}
+
+	traceDevice(d.device, d.screen, func(record TraceRecord) {
+		d.last = record
+	})
+
+	if err := d.device.Load(instructions); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Runs a single cycle and prints it. Returns the breakpoint that was hit, if any
+func (d *Debugger) step() (*Breakpoint, bool) {
+	if !d.device.Step() {
+		return nil, false
+	}
+
+	fmt.Fprintln(d.out, d.last.toString())
+	for i := range d.breakpoints {
+		if d.breakpoints[i].hit(d.last) {
+			return &d.breakpoints[i], true
+		}
+	}
+
+	return nil, true
+}
+
+// Steps until a breakpoint is hit or the program ends
+func (d *Debugger) resume(maxSteps int) {
+	for i := 0; maxSteps < 0 || i < maxSteps; i += 1 {
+		hit, ran := d.step()
+		if !ran {
+			fmt.Fprintln(d.out, "program finished")
+			return
+		}
+
+		if hit != nil {
+			fmt.Fprintln(d.out, "hit breakpoint", hit.toString())
+			return
+		}
+	}
+}
+
+// Handles a single command. Returns false once the user quits
+func (d *Debugger) handle(line string) bool {
+	tokens := strings.Fields(line)
+	if len(tokens) == 0 {
+		tokens = []string{"s"}
+	}
+
+	switch tokens[0] {
+	case "s":
+		total := 1
+		if len(tokens) > 1 {
+			n, err := strconv.Atoi(tokens[1])
+			if err != nil || n < 1 {
+				fmt.Fprintln(d.out, "step count should be a positive number")
+				return true
+			}
+			total = n
+		}
+		d.resume(total)
+	case "c":
+		d.resume(-1)
+	case "b":
+		if len(tokens) != 2 {
+			fmt.Fprintln(d.out, "usage: b cycle=N or b X=N")
+			return true
+		}
+
+		breakpoint, err := parseBreakpoint(tokens[1])
+		if err != nil {
+			fmt.Fprintln(d.out, err)
+			return true
+		}
+		d.breakpoints = append(d.breakpoints, breakpoint)
+	case "d":
+		index := -1
+		if len(tokens) == 2 {
+			if n, err := strconv.Atoi(tokens[1]); err == nil {
+				index = n
+			}
+		}
+
+		if index < 0 || index >= len(d.breakpoints) {
+			fmt.Fprintln(d.out, "no breakpoint with that index, see l")
+			return true
+		}
+		d.breakpoints = append(d.breakpoints[:index], d.breakpoints[index+1:]...)
+	case "l":
+		for i, breakpoint := range d.breakpoints {
+			fmt.Fprintf(d.out, "%v: %v\n", i, breakpoint.toString())
+		}
+	case "p":
+		fmt.Fprintln(d.out, "cycle", d.device.Cycle(), "|", formatRegisters(d.device.Registers()))
+		fmt.Fprintln(d.out, d.screen.toString())
+	case "q":
+		return false
+	case "h":
+		fmt.Fprintln(d.out, debuggerHelp)
+	default:
+		fmt.Fprintf(d.out, "unknown command %q, h for help\n", tokens[0])
+	}
+
+	return true
+}
+
+// Reads commands until the user quits or the input runs out
+func (d *Debugger) run(in io.Reader) error {
+	fmt.Fprintln(d.out, debuggerHelp)
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(d.out, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(d.out)
+			return scanner.Err()
+		}
+
+		if !d.handle(scanner.Text()) {
+			return nil
+		}
+	}
+}
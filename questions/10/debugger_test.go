@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func newTestDebugger(t *testing.T) (*Debugger, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	d, err := NewDebugger(loadExample(t), nil, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return d, out
+}
+
+func TestDebuggerBreakpoints(t *testing.T) {
+	d, out := newTestDebugger(t)
+
+	// addx 15 finishes on cycle 2, which takes X from 1 to 16
+	d.handle("b X=16")
+	d.handle("c")
+	if d.device.Cycle() != 2 || !strings.Contains(out.String(), "hit breakpoint X=16") {
+		t.Fatalf("stopped at cycle %v with output\n%v", d.device.Cycle(), out)
+	}
+
+	d.handle("b cycle=20")
+	d.handle("c")
+	if d.device.Cycle() != 20 || !strings.Contains(out.String(), "hit breakpoint cycle=20") {
+		t.Fatalf("stopped at cycle %v with output\n%v", d.device.Cycle(), out)
+	}
+
+	d.handle("s 3")
+	if d.device.Cycle() != 23 {
+		t.Errorf("s 3 stopped at cycle %v, want 23", d.device.Cycle())
+	}
+}
+
+func TestDebuggerScript(t *testing.T) {
+	d, out := newTestDebugger(t)
+	script := strings.Join([]string{
+		"b cycle=20",
+		"b X=16",
+		"l",
+		"d abc",
+		"d 5",
+		"d 1",
+		"l",
+		"c",
+		"p",
+		"q",
+		// Never reached
+		"c",
+	}, "\n")
+
+	if err := d.run(strings.NewReader(script)); err != nil {
+		t.Fatal(err)
+	}
+
+	output := out.String()
+	for _, want := range []string{
+		"0: cycle=20\n1: X=16\n",
+		"no breakpoint with that index, see l",
+		"> 0: cycle=20\n> ",
+		"hit breakpoint cycle=20",
+		"cycle 20 | X=21",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output is missing %q:\n%v", want, output)
+		}
+	}
+
+	// d abc and d 5 both failed, so only d 1 deleted anything
+	if strings.Count(output, "no breakpoint with that index") != 2 || len(d.breakpoints) != 1 {
+		t.Errorf("breakpoints left %v", d.breakpoints)
+	}
+
+	if d.device.Cycle() != 20 {
+		t.Errorf("stopped at cycle %v, want 20 since q came before the second c", d.device.Cycle())
+	}
+}
+
+func TestTraceJSONL(t *testing.T) {
+	out := &bytes.Buffer{}
+	if err := writeTrace(loadExample(t), TraceJSONL, out); err != nil {
+		t.Fatal(err)
+	}
+
+	records := []TraceRecord{}
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		var record TraceRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+
+	if len(records) != 240 {
+		t.Fatalf("got %v records, want one per cycle", len(records))
+	}
+
+	second := records[1]
+	if second.Cycle != 2 || second.PC != 0 || second.Instruction != "addx 15" {
+		t.Errorf("got %+v", second)
+	}
+	if second.Before[registerX] != 1 || second.After[registerX] != 16 {
+		t.Errorf("X went %v -> %v, want 1 -> 16", second.Before[registerX], second.After[registerX])
+	}
+	if second.PixelX != 1 || second.PixelY != 0 || !second.Lit || second.Sprite != 1 {
+		t.Errorf(
+			"got pixel %v,%v lit %v sprite %v",
+			second.PixelX,
+			second.PixelY,
+			second.Lit,
+			second.Sprite,
+		)
+	}
+
+	if err := writeTrace(loadExample(t), "xml", out); err == nil {
+		t.Error("an unknown format should be an error")
+	}
+}
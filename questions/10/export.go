@@ -0,0 +1,201 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"questions/01/main/pkg/cpu"
+)
+
+// How the screen looks when written out as an image
+type ExportOptions struct {
+	// Width and height of a single CRT pixel in the image
+	pixelSize int
+	lit       color.RGBA
+	dark      color.RGBA
+	// Marks where the beam is in each animation frame
+	beam color.RGBA
+	// Time between animation frames, in 100ths of a second
+	frameDelay int
+}
+
+var defaultExportOptions = ExportOptions{
+	pixelSize:  10,
+	lit:        color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	dark:       color.RGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xff},
+	beam:       color.RGBA{R: 0xff, G: 0x00, B: 0x00, A: 0xff},
+	frameDelay: 2,
+}
+
+// Parses colors like "#ff8800"
+func parseHexColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("color %q should look like #rrggbb", s)
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("color %q should look like #rrggbb", s)
+	}
+
+	return color.RGBA{
+		R: uint8(value >> 16),
+		G: uint8(value >> 8),
+		B: uint8(value),
+		A: 0xff,
+	}, nil
+}
+
+func toHexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// So color.RGBA can be set straight from the command line
+type ColorFlag struct {
+	color *color.RGBA
+}
+
+func (c ColorFlag) String() string {
+	if c.color == nil {
+		return ""
+	}
+
+	return toHexColor(*c.color)
+}
+
+func (c ColorFlag) Set(s string) error {
+	parsed, err := parseHexColor(s)
+	if err != nil {
+		return err
+	}
+
+	*c.color = parsed
+	return nil
+}
+
+// Palette indexes for the images
+const (
+	darkIndex uint8 = iota
+	litIndex
+	beamIndex
+)
+
+// Scales the screen up so every CRT pixel is a pixelSize square.
+// If beam is set, that pixel is drawn in the beam color
+func toImage(screen *Framebuffer, opts ExportOptions, beam *image.Point) *image.Paletted {
+	palette := color.Palette{opts.dark, opts.lit, opts.beam}
+	bounds := image.Rect(0, 0, screen.width*opts.pixelSize, screen.height*opts.pixelSize)
+	img := image.NewPaletted(bounds, palette)
+
+	for y := 0; y < screen.height; y += 1 {
+		for x := 0; x < screen.width; x += 1 {
+			index := darkIndex
+			if beam != nil && beam.X == x && beam.Y == y {
+				index = beamIndex
+			} else if screen.get(x, y) {
+				index = litIndex
+			}
+
+			for dy := 0; dy < opts.pixelSize; dy += 1 {
+				for dx := 0; dx < opts.pixelSize; dx += 1 {
+					img.SetColorIndex(x*opts.pixelSize+dx, y*opts.pixelSize+dy, index)
+				}
+			}
+		}
+	}
+
+	return img
+}
+
+func writePNG(screen *Framebuffer, opts ExportOptions, w io.Writer) error {
+	return png.Encode(w, toImage(screen, opts, nil))
+}
+
+// Draws a rect per lit pixel over a dark background
+func writeSVG(screen *Framebuffer, opts ExportOptions, w io.Writer) error {
+	width := screen.width * opts.pixelSize
+	height := screen.height * opts.pixelSize
+
+	var sb strings.Builder
+	fmt.Fprintf(
+		&sb,
+		"<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%v\" height=\"%v\" "+
+			"viewBox=\"0 0 %v %v\" shape-rendering=\"crispEdges\">\n",
+		width,
+		height,
+		width,
+		height,
+	)
+	fmt.Fprintf(&sb, "  <rect width=\"%v\" height=\"%v\" fill=\"%v\"/>\n", width, height,
+		toHexColor(opts.dark))
+
+	for y := 0; y < screen.height; y += 1 {
+		for x := 0; x < screen.width; x += 1 {
+			if !screen.get(x, y) {
+				continue
+			}
+
+			fmt.Fprintf(
+				&sb,
+				"  <rect x=\"%v\" y=\"%v\" width=\"%v\" height=\"%v\" fill=\"%v\"/>\n",
+				x*opts.pixelSize,
+				y*opts.pixelSize,
+				opts.pixelSize,
+				opts.pixelSize,
+				toHexColor(opts.lit),
+			)
+		}
+	}
+	sb.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// Animates the beam drawing the screen, one frame per cycle
+func writeGIF(instructions []cpu.Instruction, opts ExportOptions, w io.Writer) error {
+	animation := &gif.GIF{}
+
+	device := newDevice()
+	screen := NewFramebuffer(crtWidth, crtHeight)
+	device.OnDuring(func(c *cpu.CPU) {
+		screen.drawCycle(c.Cycle(), c.Register(registerX))
+
+		x, y := screen.beamPosition(c.Cycle())
+		animation.Image = append(animation.Image, toImage(screen, opts, &image.Point{X: x, Y: y}))
+		animation.Delay = append(animation.Delay, opts.frameDelay)
+	})
+
+	if err := device.Run(instructions); err != nil {
+		return err
+	}
+
+	// Finish on the full screen without the beam
+	animation.Image = append(animation.Image, toImage(screen, opts, nil))
+	animation.Delay = append(animation.Delay, opts.frameDelay)
+
+	return gif.EncodeAll(w, animation)
+}
+
+// Creates the file and hands it to write
+func writeFile(filename string, write func(w io.Writer) error) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+var testExportOptions = ExportOptions{
+	pixelSize:  3,
+	lit:        color.RGBA{R: 0x12, G: 0x34, B: 0x56, A: 0xff},
+	dark:       color.RGBA{R: 0xab, G: 0xcd, B: 0xef, A: 0xff},
+	beam:       color.RGBA{R: 0xff, G: 0x00, B: 0x00, A: 0xff},
+	frameDelay: 5,
+}
+
+func exampleFramebuffer(t *testing.T) *Framebuffer {
+	screen := NewFramebuffer(crtWidth, crtHeight)
+	runCommands(loadExample(t), map[int]bool{}, screen)
+	return screen
+}
+
+func TestWritePNG(t *testing.T) {
+	out := &bytes.Buffer{}
+	if err := writePNG(exampleFramebuffer(t), testExportOptions, out); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size := img.Bounds().Size()
+	if size.X != 40*3 || size.Y != 6*3 {
+		t.Fatalf("got %v, want 120x18", size)
+	}
+
+	// The example's top row starts ##.. so the first CRT pixel is lit and the third is dark.
+	// Check the far corner of each so the scaling is covered too
+	tests := []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{0, 0, testExportOptions.lit},
+		{2, 2, testExportOptions.lit},
+		{6, 0, testExportOptions.dark},
+		{8, 2, testExportOptions.dark},
+	}
+
+	for _, test := range tests {
+		got := color.RGBAModel.Convert(img.At(test.x, test.y)).(color.RGBA)
+		if got != test.want {
+			t.Errorf("pixel %v,%v: got %v, want %v", test.x, test.y, got, test.want)
+		}
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	out := &bytes.Buffer{}
+	if err := writeSVG(exampleFramebuffer(t), testExportOptions, out); err != nil {
+		t.Fatal(err)
+	}
+
+	svg := out.String()
+	if !strings.HasPrefix(svg, "<svg ") || !strings.HasSuffix(svg, "</svg>\n") {
+		t.Fatalf("not an svg:\n%v", svg)
+	}
+
+	// A background, and then a rect per lit pixel
+	lit := strings.Count(exampleScreen, "#")
+	if got := strings.Count(svg, "<rect "); got != lit+1 {
+		t.Errorf("got %v rects, want %v", got, lit+1)
+	}
+	if !strings.Contains(svg, `width="120" height="18" fill="#abcdef"`) {
+		t.Error("missing the dark background")
+	}
+	if !strings.Contains(svg, `<rect x="0" y="0" width="3" height="3" fill="#123456"/>`) {
+		t.Error("missing the first lit pixel")
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	instructions := loadExample(t)
+	out := &bytes.Buffer{}
+	if err := writeGIF(instructions, testExportOptions, out); err != nil {
+		t.Fatal(err)
+	}
+
+	animation, err := gif.DecodeAll(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A frame per cycle, and the finished screen
+	want := programCycles(instructions) + 1
+	if len(animation.Image) != want {
+		t.Errorf("got %v frames, want %v", len(animation.Image), want)
+	}
+	for i, delay := range animation.Delay {
+		if delay != 5 {
+			t.Fatalf("frame %v has delay %v, want 5", i, delay)
+		}
+	}
+}
+
+func TestParseHexColor(t *testing.T) {
+	got, err := parseHexColor("#ff8800")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != (color.RGBA{R: 0xff, G: 0x88, B: 0x00, A: 0xff}) {
+		t.Errorf("got %v", got)
+	}
+
+	for _, bad := range []string{"#12345", "zzzzzz", "#1234567", ""} {
+		if _, err := parseHexColor(bad); err == nil {
+			t.Errorf("%q should be rejected", bad)
+		}
+	}
+}
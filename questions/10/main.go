@@ -1,36 +1,33 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"os"
-	"strconv"
+	"sort"
 	"strings"
-)
-
-type Command int64
 
-const (
-	Noop Command = iota
-	Addx
+	"questions/01/main/pkg/cpu"
 )
 
-type Instruction struct {
-	command Command
-	value   int
+// The handheld device only has the one register
+const registerX = "X"
+
+var instructionSet = []cpu.Opcode{
+	{Name: "noop", Cycles: 1},
+	{
+		Name:     "addx",
+		Cycles:   2,
+		Operands: 1,
+		Execute: func(regs cpu.Registers, args []int) {
+			regs[registerX] += args[0]
+		},
+	},
 }
 
-var inputToCommandMap = map[string]Command{
-	"noop": Noop,
-	"addx": Addx,
-}
-
-func parseToIntOrPanic(s string) int {
-	i, err := strconv.Atoi(s)
-	if err != nil {
-		panic(err)
-	}
-
-	return i
+func newDevice() *cpu.CPU {
+	return cpu.New(instructionSet, cpu.Registers{registerX: 1})
 }
 
 func readLines(inputFile string) []string {
@@ -42,113 +39,201 @@ func readLines(inputFile string) []string {
 	return strings.Split(string(dat), "\n")
 }
 
-func parseInstructions(input []string) []Instruction {
-	instructions := []Instruction{}
-	for _, line := range input {
-		tokens := strings.Split(line, " ")
-		instruction := Instruction{
-			command: inputToCommandMap[tokens[0]],
-		}
-		if instruction.command == Addx {
-			instruction.value = parseToIntOrPanic(tokens[1])
-		}
-		instructions = append(instructions, instruction)
-	}
-
-	return instructions
+func parseInstructions(input []string) ([]cpu.Instruction, error) {
+	return cpu.Assemble(instructionSet, input)
 }
 
+// Runs the program, recording the register at each tracked cycle.
+// If a screen is given, the CRT draws to it as the program runs
 func runCommands(
-	instructions []Instruction,
+	instructions []cpu.Instruction,
 	cyclesToTrack map[int]bool,
-	drawWhileRunning bool,
+	screen *Framebuffer,
 ) map[int]int {
-	register := 1
-	maxInstrTime := 2
-
+	device := newDevice()
 	cycleToValue := map[int]int{}
 
-	cycleOperationBuffer := make([]int, maxInstrTime)
-	for i := 0; i < maxInstrTime; i += 1 {
-		cycleOperationBuffer[i] = 0
-	}
+	// We calculate during the cycle, not at the end
+	device.OnDuring(func(c *cpu.CPU) {
+		cycle := c.Cycle()
+		register := c.Register(registerX)
 
-	newlineCycleEvery := 40
-
-	cycle := 0
-	for _, instruction := range instructions {
-		instructionLength := 0
-		// Process instruction
-		if instruction.command == Addx {
-			instructionLength = 2
-			cycleOperationBuffer[(cycle+1)%maxInstrTime] += instruction.value
-		} else if instruction.command == Noop {
-			instructionLength = 1
+		_, exists := cyclesToTrack[cycle]
+		if exists {
+			cycleToValue[cycle] = register
 		}
 
-		for i := 0; i < instructionLength; i += 1 {
-			// The question is 1 indexed instead of 0, and we calculate
-			// during the cycle, not at the end
-			_, exists := cyclesToTrack[cycle+1]
-			if exists {
-				cycleToValue[cycle+1] = register
-			}
-
-			// Draw pixel
-			if drawWhileRunning {
-				if cycle%newlineCycleEvery == 0 {
-					fmt.Println()
-				}
-
-				pixelPos := cycle % newlineCycleEvery
-				if pixelPos-1 <= register && pixelPos+1 >= register {
-					fmt.Print("#")
-				} else {
-					fmt.Print(".")
-				}
-			}
-
-			register += cycleOperationBuffer[cycle%maxInstrTime]
-			cycleOperationBuffer[cycle%maxInstrTime] = 0
-
-			cycle += 1
+		if screen != nil {
+			screen.drawCycle(cycle, register)
 		}
+	})
+
+	if err := device.Run(instructions); err != nil {
+		panic(err)
 	}
 
 	return cycleToValue
 }
 
-func calcSignalStrength(cycleToValue map[int]int) int {
-	signalStrength := 0
+// How many cycles the program takes to run
+func programCycles(instructions []cpu.Instruction) int {
+	device := newDevice()
+	if err := device.Run(instructions); err != nil {
+		panic(err)
+	}
+
+	return device.Cycle()
+}
+
+// Returns the total signal strength, along with each sample in cycle order
+func calcSignalStrength(cycleToValue map[int]int) (int, []Sample) {
+	samples := []Sample{}
 	for cycle, value := range cycleToValue {
-		signalStrength = signalStrength + (cycle * value)
+		samples = append(samples, Sample{cycle: cycle, value: value, strength: cycle * value})
+	}
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].cycle < samples[j].cycle
+	})
+
+	signalStrength := 0
+	for _, sample := range samples {
+		signalStrength += sample.strength
 	}
 
-	return signalStrength
+	return signalStrength, samples
 }
 
-func parts() {
+func parts(schedule Schedule, showSamples bool) {
 	input := readLines("./input.txt")
-	instructions := parseInstructions(input)
+	instructions, err := parseInstructions(input)
+	if err != nil {
+		panic(err)
+	}
 
-	cyclesToTrack := map[int]bool{
-		20:  true,
-		60:  true,
-		100: true,
-		140: true,
-		180: true,
-		220: true,
+	if err := schedule.checkLength(programCycles(instructions)); err != nil {
+		panic(err)
 	}
 
-	cycleToValue := runCommands(instructions, cyclesToTrack, false)
-	signalStrength := calcSignalStrength(cycleToValue)
+	cyclesToTrack := schedule.cyclesToTrack()
+
+	cycleToValue := runCommands(instructions, cyclesToTrack, nil)
+	signalStrength, samples := calcSignalStrength(cycleToValue)
+
+	if showSamples {
+		for _, sample := range samples {
+			fmt.Println(sample.toString())
+		}
+	}
 
 	fmt.Println("Part 1 - ", signalStrength)
 
-	fmt.Println("Part 2")
-	runCommands(instructions, cyclesToTrack, true)
+	screen := NewFramebuffer(crtWidth, crtHeight)
+	runCommands(instructions, cyclesToTrack, screen)
+
+	letters, err := readLetters(screen)
+	if err != nil {
+		panic(fmt.Sprintf("%v\n%v", err, screen.toString()))
+	}
+
+	fmt.Println("Part 2 - ", letters)
+	fmt.Println(screen.toString())
+}
+
+// Writes out whichever images were asked for on the command line
+func exportImages(instructions []cpu.Instruction, opts ExportOptions) {
+	screen := NewFramebuffer(crtWidth, crtHeight)
+	runCommands(instructions, map[int]bool{}, screen)
+
+	exports := []struct {
+		filename string
+		write    func(w io.Writer) error
+	}{
+		{*pngFile, func(w io.Writer) error { return writePNG(screen, opts, w) }},
+		{*svgFile, func(w io.Writer) error { return writeSVG(screen, opts, w) }},
+		{*gifFile, func(w io.Writer) error { return writeGIF(instructions, opts, w) }},
+	}
+
+	for _, export := range exports {
+		if export.filename == "" {
+			continue
+		}
+
+		if err := writeFile(export.filename, export.write); err != nil {
+			panic(err)
+		}
+	}
 }
 
+var (
+	traceFormat = flag.String("trace", "", "write a record per cycle, as text or jsonl, and exit")
+	stepMode    = flag.Bool("step", false, "step through the program interactively")
+	showSamples = flag.Bool("samples", false, "print each sample that makes up the Part 1 signal")
+	sampleList  = flag.String("sample-cycles", "", "explicit cycles to sample, like 20,60,100")
+	pngFile     = flag.String("png", "", "write the Part 2 screen to this PNG file")
+	svgFile     = flag.String("svg", "", "write the Part 2 screen to this SVG file")
+	gifFile     = flag.String("gif", "", "write an animation of the beam drawing the screen")
+	breakpoints BreakpointFlags
+)
+
 func main() {
-	parts()
+	flag.Var(&breakpoints, "break", "breakpoint for -step, like cycle=20 or X=5. Can be repeated")
+	schedule := defaultSchedule
+	flag.IntVar(&schedule.start, "sample-start", schedule.start, "first cycle to sample")
+	flag.IntVar(&schedule.stride, "sample-stride", schedule.stride, "cycles between samples")
+	flag.IntVar(&schedule.end, "sample-end", schedule.end, "last cycle to sample")
+
+	exportOpts := defaultExportOptions
+	flag.IntVar(&exportOpts.pixelSize, "pixel-size", exportOpts.pixelSize, "pixels per CRT pixel")
+	flag.IntVar(&exportOpts.frameDelay, "frame-delay", exportOpts.frameDelay, "GIF delay in 1/100s")
+	flag.Var(ColorFlag{&exportOpts.lit}, "lit-color", "color of lit pixels, like #ffffff")
+	flag.Var(ColorFlag{&exportOpts.dark}, "dark-color", "color of dark pixels, like #000000")
+	flag.Var(ColorFlag{&exportOpts.beam}, "beam-color", "color of the beam in the GIF")
+	flag.Parse()
+
+	if *sampleList != "" {
+		cycles, err := parseCycleList(*sampleList)
+		if err != nil {
+			panic(err)
+		}
+		schedule.cycles = cycles
+	}
+
+	if err := schedule.validate(); err != nil {
+		panic(err)
+	}
+
+	if exportOpts.pixelSize < 1 {
+		panic("pixel size should be at least 1")
+	}
+
+	if *traceFormat == "" && !*stepMode {
+		parts(schedule, *showSamples)
+	}
+
+	instructions, err := parseInstructions(readLines("./input.txt"))
+	if err != nil {
+		panic(err)
+	}
+
+	if *traceFormat == "" && !*stepMode {
+		exportImages(instructions, exportOpts)
+		return
+	}
+
+	if *stepMode {
+		debugger, err := NewDebugger(instructions, breakpoints, os.Stdout)
+		if err != nil {
+			panic(err)
+		}
+
+		if err := debugger.run(os.Stdin); err != nil {
+			panic(err)
+		}
+		return
+	}
+
+	if err := writeTrace(instructions, TraceFormat(*traceFormat), os.Stdout); err != nil {
+		panic(err)
+	}
 }
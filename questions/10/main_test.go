@@ -0,0 +1,108 @@
+package main
+
+import (
+	"testing"
+
+	"questions/01/main/pkg/cpu"
+)
+
+func loadExample(t *testing.T) []cpu.Instruction {
+	instructions, err := parseInstructions(readLines("./example.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return instructions
+}
+
+// The screen the puzzle's larger example draws
+const exampleScreen = `##..##..##..##..##..##..##..##..##..##..
+###...###...###...###...###...###...###.
+####....####....####....####....####....
+#####.....#####.....#####.....#####.....
+######......######......######......####
+#######.......#######.......#######.....`
+
+func TestExampleScreen(t *testing.T) {
+	screen := NewFramebuffer(crtWidth, crtHeight)
+	runCommands(loadExample(t), map[int]bool{}, screen)
+
+	if got := screen.toString(); got != exampleScreen {
+		t.Errorf("got\n%v\nwant\n%v", got, exampleScreen)
+	}
+}
+
+func TestReadLetters(t *testing.T) {
+	// Draws the glyphs side by side, the same way the CRT lays them out
+	word := "REHPRLUB"
+	patterns := map[rune]string{}
+	for pattern, letter := range glyphs {
+		patterns[letter] = pattern
+	}
+
+	screen := NewFramebuffer(crtWidth, crtHeight)
+	for i, letter := range word {
+		pattern := patterns[letter]
+		for p, pixel := range pattern {
+			x := i*(glyphWidth+glyphSpacing) + p%glyphWidth
+			screen.set(x, p/glyphWidth, pixel == '#')
+		}
+	}
+
+	got, err := readLetters(screen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != word {
+		t.Errorf("got %v, want %v", got, word)
+	}
+
+	// The example screen isn't made of letters
+	example := NewFramebuffer(crtWidth, crtHeight)
+	runCommands(loadExample(t), map[int]bool{}, example)
+	if _, err := readLetters(example); err == nil {
+		t.Error("reading the example screen should fail")
+	}
+}
+
+func TestExamplePart1(t *testing.T) {
+	cycleToValue := runCommands(loadExample(t), defaultSchedule.cyclesToTrack(), nil)
+	if got, _ := calcSignalStrength(cycleToValue); got != 13140 {
+		t.Errorf("got %v, want 13140", got)
+	}
+}
+
+func TestExampleSamples(t *testing.T) {
+	instructions := loadExample(t)
+	if err := defaultSchedule.checkLength(programCycles(instructions)); err != nil {
+		t.Fatal(err)
+	}
+
+	_, samples := calcSignalStrength(runCommands(instructions, defaultSchedule.cyclesToTrack(), nil))
+
+	// From the puzzle's step by step example
+	want := []Sample{
+		{cycle: 20, value: 21, strength: 420},
+		{cycle: 60, value: 19, strength: 1140},
+		{cycle: 100, value: 18, strength: 1800},
+		{cycle: 140, value: 21, strength: 2940},
+		{cycle: 180, value: 16, strength: 2880},
+		{cycle: 220, value: 18, strength: 3960},
+	}
+
+	if len(samples) != len(want) {
+		t.Fatalf("got %v samples, want %v", len(samples), len(want))
+	}
+	for i := range want {
+		if samples[i] != want[i] {
+			t.Errorf("got %v, want %v", samples[i].toString(), want[i].toString())
+		}
+	}
+}
+
+func TestSamplesPastTheEnd(t *testing.T) {
+	schedule := Schedule{cycles: []int{20, 500}}
+	if err := schedule.checkLength(programCycles(loadExample(t))); err == nil {
+		t.Error("cycle 500 is past the end of the example, and should be an error")
+	}
+}
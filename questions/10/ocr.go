@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Letters are 4 pixels wide and 6 tall, with a blank column after each one
+const (
+	glyphWidth   = 4
+	glyphHeight  = 6
+	glyphSpacing = 1
+)
+
+// The capital letters the puzzles draw with. Not every letter has shown up,
+// so this only has the ones that have
+var glyphs = map[string]rune{
+	".##.#..##..######..##..#": 'A',
+	"###.#..####.#..##..####.": 'B',
+	".##.#..##...#...#..#.##.": 'C',
+	"#####...###.#...#...####": 'E',
+	"#####...###.#...#...#...": 'F',
+	".##.#..##...#.###..#.###": 'G',
+	"#..##..######..##..##..#": 'H',
+	".###..#...#...#...#..###": 'I',
+	"..##...#...#...##..#.##.": 'J',
+	"#..##.#.##..#.#.#.#.#..#": 'K',
+	"#...#...#...#...#...####": 'L',
+	".##.#..##..##..##..#.##.": 'O',
+	"###.#..##..####.#...#...": 'P',
+	"###.#..##..####.#.#.#..#": 'R',
+	".####...#....##....####.": 'S',
+	"#..##..##..##..##..#.##.": 'U',
+	"####...#..#..#..#...####": 'Z',
+}
+
+// Reads the glyph whose top left corner is at column x
+func readGlyph(screen *Framebuffer, x int) string {
+	var sb strings.Builder
+	for y := 0; y < glyphHeight; y += 1 {
+		for dx := 0; dx < glyphWidth; dx += 1 {
+			if screen.get(x+dx, y) {
+				sb.WriteString("#")
+			} else {
+				sb.WriteString(".")
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// Reads the letters drawn on the screen, left to right
+func readLetters(screen *Framebuffer) (string, error) {
+	if screen.height != glyphHeight {
+		return "", fmt.Errorf(
+			"screen is %v pixels tall, but letters are %v",
+			screen.height,
+			glyphHeight,
+		)
+	}
+
+	letters := ""
+	for x := 0; x+glyphWidth <= screen.width; x += glyphWidth + glyphSpacing {
+		letter, exists := glyphs[readGlyph(screen, x)]
+		if !exists {
+			return "", fmt.Errorf("unrecognised letter at column %v", x)
+		}
+
+		letters += string(letter)
+	}
+
+	return letters, nil
+}
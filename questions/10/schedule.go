@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Which cycles to sample the register on. Either every stride cycles from start to end
+// (inclusive), or an explicit list of cycles
+type Schedule struct {
+	start  int
+	stride int
+	end    int
+	cycles []int
+}
+
+// The cycles the puzzle asks for
+var defaultSchedule = Schedule{start: 20, stride: 40, end: 220}
+
+// Parses a comma separated list of cycles like "20,60,100"
+func parseCycleList(s string) ([]int, error) {
+	cycles := []int{}
+	for _, token := range strings.Split(s, ",") {
+		cycle, err := strconv.Atoi(strings.TrimSpace(token))
+		if err != nil {
+			return nil, fmt.Errorf("cycle %q is not a number", token)
+		}
+		cycles = append(cycles, cycle)
+	}
+
+	return cycles, nil
+}
+
+func (s Schedule) validate() error {
+	if len(s.cycles) > 0 {
+		for _, cycle := range s.cycles {
+			if cycle < 1 {
+				return fmt.Errorf("cycle %v is before the first cycle", cycle)
+			}
+		}
+		return nil
+	}
+
+	if s.start < 1 {
+		return fmt.Errorf("start %v is before the first cycle", s.start)
+	}
+
+	if s.stride < 1 {
+		return fmt.Errorf("stride %v should be at least 1", s.stride)
+	}
+
+	if s.end < s.start {
+		return fmt.Errorf("end %v is before start %v", s.end, s.start)
+	}
+
+	return nil
+}
+
+// Cycles past the end of the program never run, so they'd quietly drop out of the total
+func (s Schedule) checkLength(lastCycle int) error {
+	missing := []int{}
+	for cycle := range s.cyclesToTrack() {
+		if cycle > lastCycle {
+			missing = append(missing, cycle)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	sort.Ints(missing)
+	return fmt.Errorf("cycles %v are past the end of the program at cycle %v", missing, lastCycle)
+}
+
+func (s Schedule) cyclesToTrack() map[int]bool {
+	cycles := map[int]bool{}
+	if len(s.cycles) > 0 {
+		for _, cycle := range s.cycles {
+			cycles[cycle] = true
+		}
+		return cycles
+	}
+
+	for cycle := s.start; cycle <= s.end; cycle += s.stride {
+		cycles[cycle] = true
+	}
+
+	return cycles
+}
+
+// The register during a sampled cycle, and the signal strength it gives
+type Sample struct {
+	cycle    int
+	value    int
+	strength int
+}
+
+func (s Sample) toString() string {
+	return fmt.Sprintf("cycle %v: %v * %v = %v", s.cycle, s.cycle, s.value, s.strength)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"questions/01/main/pkg/cpu"
+)
+
+type TraceFormat string
+
+const (
+	TraceText  TraceFormat = "text"
+	TraceJSONL TraceFormat = "jsonl"
+)
+
+// Everything that happened in a single cycle
+type TraceRecord struct {
+	Cycle       int           `json:"cycle"`
+	PC          int           `json:"pc"`
+	Instruction string        `json:"instruction"`
+	Before      cpu.Registers `json:"before"`
+	After       cpu.Registers `json:"after"`
+	PixelX      int           `json:"pixelX"`
+	PixelY      int           `json:"pixelY"`
+	Lit         bool          `json:"lit"`
+	Sprite      int           `json:"sprite"`
+}
+
+// Registers in name order, like "X=1"
+func formatRegisters(regs cpu.Registers) string {
+	names := []string{}
+	for name := range regs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := []string{}
+	for _, name := range names {
+		values = append(values, fmt.Sprintf("%v=%v", name, regs[name]))
+	}
+
+	return strings.Join(values, " ")
+}
+
+func (r TraceRecord) toString() string {
+	pixel := "."
+	if r.Lit {
+		pixel = "#"
+	}
+
+	return fmt.Sprintf(
+		"cycle %4v | %-8v | %v -> %v | pixel %2v,%v %v | sprite %v..%v",
+		r.Cycle,
+		r.Instruction,
+		formatRegisters(r.Before),
+		formatRegisters(r.After),
+		r.PixelX,
+		r.PixelY,
+		pixel,
+		r.Sprite-1,
+		r.Sprite+1,
+	)
+}
+
+// Hooks into the device so onRecord is called at the end of every cycle.
+// The CRT draws to the screen as it goes
+func traceDevice(device *cpu.CPU, screen *Framebuffer, onRecord func(TraceRecord)) {
+	var record TraceRecord
+
+	device.OnDuring(func(c *cpu.CPU) {
+		instruction, _ := c.Current()
+		sprite := c.Register(registerX)
+
+		x, y := screen.beamPosition(c.Cycle())
+		screen.drawCycle(c.Cycle(), sprite)
+
+		record = TraceRecord{
+			Cycle:       c.Cycle(),
+			PC:          c.PC(),
+			Instruction: instruction.String(),
+			Before:      c.Registers(),
+			PixelX:      x,
+			PixelY:      y,
+			Lit:         screen.get(x, y),
+			Sprite:      sprite,
+		}
+	})
+
+	device.OnAfter(func(c *cpu.CPU) {
+		record.After = c.Registers()
+		onRecord(record)
+	})
+}
+
+// Runs the whole program, writing a record per cycle
+func writeTrace(instructions []cpu.Instruction, format TraceFormat, w io.Writer) error {
+	var emit func(TraceRecord) error
+	switch format {
+	case TraceText:
+		emit = func(record TraceRecord) error {
+			_, err := fmt.Fprintln(w, record.toString())
+			return err
+		}
+	case TraceJSONL:
+		encoder := json.NewEncoder(w)
+		emit = func(record TraceRecord) error {
+			return encoder.Encode(record)
+		}
+	default:
+		return fmt.Errorf("unknown trace format %q", format)
+	}
+
+	var writeErr error
+	device := newDevice()
+	traceDevice(device, NewFramebuffer(crtWidth, crtHeight), func(record TraceRecord) {
+		// Keep the first error, there's no way to stop the device from a hook
+		if writeErr == nil {
+			writeErr = emit(record)
+		}
+	})
+
+	if err := device.Run(instructions); err != nil {
+		return err
+	}
+
+	return writeErr
+}